@@ -24,10 +24,26 @@ func main() {
 			fmt.Println("convert is an experimental feature not yet fully supported, use flag '--experimental' to run convert.")
 			os.Exit(0)
 		}
+	case "classify":
+		run.Classify(args)
+	case "extract":
+		run.Extract(args)
+	case "fix":
+		run.Fix(args)
 	case "merge":
 		run.Merge(args)
+	case "normalize":
+		run.Normalize(args)
+	case "player":
+		run.Player(args)
 	case "query":
 		run.Query(args)
+	case "stats":
+		run.Stats(args)
+	case "tree":
+		run.Tree(args)
+	case "validate":
+		run.Validate(args)
 	default:
 		global.Logger.Error(fmt.Sprintf("invalid program: %s", program))
 		os.Exit(1)
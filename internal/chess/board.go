@@ -0,0 +1,119 @@
+package chess
+
+import (
+	"fmt"
+)
+
+type Color int
+
+const (
+	White Color = iota
+	Black
+)
+
+func (c Color) Other() Color {
+	return 1 - c
+}
+
+type PieceType int
+
+const (
+	NoPieceType PieceType = iota
+	Pawn
+	Knight
+	Bishop
+	Rook
+	Queen
+	King
+)
+
+type Piece struct {
+	Type  PieceType
+	Color Color
+}
+
+var NoPiece = Piece{}
+
+func (p Piece) IsEmpty() bool {
+	return p.Type == NoPieceType
+}
+
+// Square indexes the board from a1 (0) to h8 (63), file first.
+type Square int
+
+const NoSquare Square = -1
+
+func NewSquare(file int, rank int) Square {
+	return Square(rank*8 + file)
+}
+
+func (sq Square) File() int {
+	return int(sq) % 8
+}
+
+func (sq Square) Rank() int {
+	return int(sq) / 8
+}
+
+func (sq Square) String() string {
+	if sq < 0 || sq > 63 {
+		return "-"
+	}
+
+	return fmt.Sprintf("%c%c", 'a'+sq.File(), '1'+sq.Rank())
+}
+
+func ParseSquare(str string) (Square, error) {
+	if len(str) != 2 || str[0] < 'a' || str[0] > 'h' || str[1] < '1' || str[1] > '8' {
+		return NoSquare, fmt.Errorf("invalid square: %s", str)
+	}
+
+	return NewSquare(int(str[0]-'a'), int(str[1]-'1')), nil
+}
+
+type Castling int
+
+const (
+	WhiteKingside Castling = 1 << iota
+	WhiteQueenside
+	BlackKingside
+	BlackQueenside
+)
+
+type Position struct {
+	Board          [64]Piece
+	Turn           Color
+	Castling       Castling
+	EnPassant      Square
+	HalfmoveClock  int
+	FullmoveNumber int
+}
+
+func StartingPosition() *Position {
+	pos, err := ParseFEN(StartingFEN)
+	if err != nil {
+		panic(err)
+	}
+
+	return pos
+}
+
+func (p *Position) Clone() *Position {
+	clone := *p
+	return &clone
+}
+
+func (p *Position) PieceAt(sq Square) Piece {
+	return p.Board[sq]
+}
+
+func (p *Position) KingSquare(color Color) Square {
+	for sq := Square(0); sq < 64; sq++ {
+		piece := p.Board[sq]
+		if piece.Type == King && piece.Color == color {
+			return sq
+		}
+	}
+
+	return NoSquare
+}
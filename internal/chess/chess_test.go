@@ -0,0 +1,224 @@
+package chess
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func perft(pos *Position, depth int) int {
+	if depth == 0 {
+		return 1
+	}
+
+	nodes := 0
+	for _, move := range pos.LegalMoves() {
+		nodes += perft(pos.Play(move), depth-1)
+	}
+
+	return nodes
+}
+
+func TestPerft(t *testing.T) {
+	samples := []struct {
+		fen      string
+		depth    int
+		expected int
+	}{
+		{StartingFEN, 3, 8902},
+		{"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1", 2, 2039},
+		{"8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1", 3, 2812},
+		{"r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1", 2, 264},
+	}
+
+	for _, sample := range samples {
+		pos, err := ParseFEN(sample.fen)
+		if err != nil {
+			t.Fatalf("An error occured parsing fen: %v", err)
+		}
+
+		result := perft(pos, sample.depth)
+		if result != sample.expected {
+			t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", result, sample.expected)
+		}
+	}
+}
+
+func TestFEN(t *testing.T) {
+	pos := StartingPosition()
+
+	for _, san := range []string{"e4", "c5", "Nf3"} {
+		move, err := pos.ParseSAN(san)
+		if err != nil {
+			t.Fatalf("An error occured parsing move %s: %v", san, err)
+		}
+		pos = pos.Play(move)
+	}
+
+	expected := "rnbqkbnr/pp1ppppp/8/2p5/4P3/5N2/PPPP1PPP/RNBQKB1R b KQkq - 1 2"
+	if pos.FEN() != expected {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", pos.FEN(), expected)
+	}
+
+	expected = "rnbqkbnr/pp1ppppp/8/2p5/4P3/5N2/PPPP1PPP/RNBQKB1R b KQkq -"
+	if pos.EPD() != expected {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", pos.EPD(), expected)
+	}
+}
+
+func TestSAN(t *testing.T) {
+	pos, err := ParseFEN("r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1")
+	if err != nil {
+		t.Fatalf("An error occured parsing fen: %v", err)
+	}
+
+	samples := map[string]string{
+		"0-0":    "O-O",
+		"O-O-O":  "O-O-O",
+		"Nxf7":   "Nxf7",
+		"Ne5xf7": "Nxf7",
+		"Qf3h3":  "Qxh3",
+		"dxe6":   "dxe6",
+		"Bd2-h6": "Bh6",
+		"Ng4":    "Ng4",
+	}
+
+	for input, expected := range samples {
+		move, err := pos.ParseSAN(input)
+		if err != nil {
+			t.Errorf("An error occured parsing move %s: %v", input, err)
+			continue
+		}
+
+		result := pos.SAN(move)
+		if result != expected {
+			t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", result, expected)
+		}
+	}
+
+	_, err = pos.ParseSAN("Ke3")
+	if !errors.Is(err, ErrIllegalMove) {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", err, ErrIllegalMove)
+	}
+
+	pos, err = ParseFEN("4k3/8/8/8/8/8/4K3/R6R w - - 0 1")
+	if err != nil {
+		t.Fatalf("An error occured parsing fen: %v", err)
+	}
+
+	_, err = pos.ParseSAN("Rd1")
+	if !errors.Is(err, ErrAmbiguousMove) {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", err, ErrAmbiguousMove)
+	}
+
+	move, err := pos.ParseSAN("Rad1")
+	if err != nil {
+		t.Fatalf("An error occured parsing move: %v", err)
+	}
+
+	if pos.SAN(move) != "Rad1" {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", pos.SAN(move), "Rad1")
+	}
+
+	pos, err = ParseFEN("7k/4P3/6K1/8/8/8/8/8 w - - 0 1")
+	if err != nil {
+		t.Fatalf("An error occured parsing fen: %v", err)
+	}
+
+	move, err = pos.ParseSAN("e8Q")
+	if err != nil {
+		t.Fatalf("An error occured parsing move: %v", err)
+	}
+
+	if pos.SAN(move) != "e8=Q#" {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", pos.SAN(move), "e8=Q#")
+	}
+}
+
+func TestNotation(t *testing.T) {
+	pos, err := ParseFEN("r3k2r/1P6/8/3p4/4P3/5N2/8/R3K2R w KQkq - 0 1")
+	if err != nil {
+		t.Fatalf("An error occured parsing fen: %v", err)
+	}
+
+	samples := []struct {
+		san      string
+		uci      string
+		lan      string
+		figurine string
+	}{
+		{san: "Nd4", uci: "f3d4", lan: "Nf3-d4", figurine: "♘d4"},
+		{san: "exd5", uci: "e4d5", lan: "e4xd5", figurine: "exd5"},
+		{san: "bxa8=Q+", uci: "b7a8q", lan: "b7xa8=Q+", figurine: "bxa8=♕+"},
+		{san: "O-O-O", uci: "e1c1", lan: "O-O-O", figurine: "O-O-O"},
+	}
+
+	for _, sample := range samples {
+		move, err := pos.ParseSAN(sample.san)
+		if err != nil {
+			t.Fatalf("An error occured parsing %s: %v", sample.san, err)
+		}
+
+		result := []string{move.UCI(), pos.LAN(move), pos.Figurine(move)}
+		expected := []string{sample.uci, sample.lan, sample.figurine}
+
+		if !reflect.DeepEqual(result, expected) {
+			t.Errorf("Incorrect Result for %s: \nresult: %v \nexpected: %v", sample.san, result, expected)
+		}
+	}
+}
+
+func TestTranslateSAN(t *testing.T) {
+	german, err := ParseLanguage("de")
+	if err != nil {
+		t.Fatalf("An error occured parsing language: %v", err)
+	}
+
+	spanish, err := ParseLanguage("ES")
+	if err != nil {
+		t.Fatalf("An error occured parsing language: %v", err)
+	}
+
+	samples := []struct {
+		san      string
+		from     Language
+		to       Language
+		expected string
+	}{
+		{san: "Sf3", from: german, to: English, expected: "Nf3"},
+		{san: "e8=D+", from: german, to: English, expected: "e8=Q+"},
+		{san: "0-0-0", from: german, to: English, expected: "0-0-0"},
+		{san: "Rxe2", from: English, to: spanish, expected: "Txe2"},
+		{san: "Kg1", from: English, to: spanish, expected: "Rg1"},
+		{san: "Ng1-f3", from: English, to: spanish, expected: "Cg1-f3"},
+		{san: "Axc6", from: spanish, to: german, expected: "Lxc6"},
+	}
+
+	for _, sample := range samples {
+		result := TranslateSAN(sample.san, sample.from, sample.to)
+		if result != sample.expected {
+			t.Errorf("Incorrect Result for %s: \nresult: %v \nexpected: %v", sample.san, result, sample.expected)
+		}
+	}
+
+	_, err = ParseLanguage("xx")
+	if err == nil {
+		t.Errorf("Expected an error parsing unknown language")
+	}
+}
+
+func TestNumbered(t *testing.T) {
+	pos := StartingPosition()
+	if result := pos.Numbered("e4"); result != "1. e4" {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", result, "1. e4")
+	}
+
+	move, err := pos.ParseSAN("e4")
+	if err != nil {
+		t.Fatalf("An error occured parsing move: %v", err)
+	}
+
+	if result := pos.Play(move).Numbered("e5"); result != "1... e5" {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", result, "1... e5")
+	}
+}
@@ -0,0 +1,195 @@
+package chess
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const StartingFEN = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"
+
+var fenPieces = map[rune]Piece{
+	'P': {Pawn, White}, 'N': {Knight, White}, 'B': {Bishop, White},
+	'R': {Rook, White}, 'Q': {Queen, White}, 'K': {King, White},
+	'p': {Pawn, Black}, 'n': {Knight, Black}, 'b': {Bishop, Black},
+	'r': {Rook, Black}, 'q': {Queen, Black}, 'k': {King, Black},
+}
+
+func ParseFEN(fen string) (*Position, error) {
+	fields := strings.Fields(fen)
+	if len(fields) < 4 {
+		return nil, fmt.Errorf("invalid FEN, expected atleast 4 fields: %s", fen)
+	}
+
+	pos := &Position{EnPassant: NoSquare, FullmoveNumber: 1}
+
+	ranks := strings.Split(fields[0], "/")
+	if len(ranks) != 8 {
+		return nil, fmt.Errorf("invalid FEN, expected 8 ranks: %s", fen)
+	}
+
+	for i, rank := range ranks {
+		file := 0
+		for _, c := range rank {
+			if c >= '1' && c <= '8' {
+				file += int(c - '0')
+				continue
+			}
+
+			piece, ok := fenPieces[c]
+			if !ok || file > 7 {
+				return nil, fmt.Errorf("invalid FEN piece placement: %s", fields[0])
+			}
+
+			pos.Board[NewSquare(file, 7-i)] = piece
+			file++
+		}
+
+		if file != 8 {
+			return nil, fmt.Errorf("invalid FEN rank length: %s", rank)
+		}
+	}
+
+	switch fields[1] {
+	case "w":
+		pos.Turn = White
+	case "b":
+		pos.Turn = Black
+	default:
+		return nil, fmt.Errorf("unexpected character in FEN: %s", fields[1])
+	}
+
+	if fields[2] != "-" {
+		for _, c := range fields[2] {
+			switch c {
+			case 'K':
+				pos.Castling |= WhiteKingside
+			case 'Q':
+				pos.Castling |= WhiteQueenside
+			case 'k':
+				pos.Castling |= BlackKingside
+			case 'q':
+				pos.Castling |= BlackQueenside
+			default:
+				return nil, fmt.Errorf("unexpected castling rights in FEN: %s", fields[2])
+			}
+		}
+	}
+
+	if fields[3] != "-" {
+		sq, err := ParseSquare(fields[3])
+		if err != nil {
+			return nil, fmt.Errorf("invalid en passant square in FEN: %s", fields[3])
+		}
+		pos.EnPassant = sq
+	}
+
+	if len(fields) >= 6 {
+		halfmove, err := strconv.Atoi(fields[4])
+		if err != nil {
+			return nil, fmt.Errorf("invalid halfmove clock in FEN: %s", fields[4])
+		}
+
+		fullmove, err := strconv.Atoi(fields[5])
+		if err != nil {
+			return nil, fmt.Errorf("invalid fullmove number in FEN: %s", fields[5])
+		}
+
+		pos.HalfmoveClock = halfmove
+		pos.FullmoveNumber = fullmove
+	}
+
+	if pos.KingSquare(White) == NoSquare || pos.KingSquare(Black) == NoSquare {
+		return nil, fmt.Errorf("invalid FEN, both sides need a king: %s", fen)
+	}
+
+	return pos, nil
+}
+
+func (p *Position) placement() string {
+	var sb strings.Builder
+
+	for rank := 7; rank >= 0; rank-- {
+		empty := 0
+		for file := range 8 {
+			piece := p.Board[NewSquare(file, rank)]
+			if piece.IsEmpty() {
+				empty++
+				continue
+			}
+
+			if empty > 0 {
+				sb.WriteString(strconv.Itoa(empty))
+				empty = 0
+			}
+			sb.WriteRune(pieceRune(piece))
+		}
+
+		if empty > 0 {
+			sb.WriteString(strconv.Itoa(empty))
+		}
+
+		if rank > 0 {
+			sb.WriteString("/")
+		}
+	}
+
+	return sb.String()
+}
+
+func (p *Position) castlingString() string {
+	castling := ""
+	if p.Castling&WhiteKingside != 0 {
+		castling += "K"
+	}
+	if p.Castling&WhiteQueenside != 0 {
+		castling += "Q"
+	}
+	if p.Castling&BlackKingside != 0 {
+		castling += "k"
+	}
+	if p.Castling&BlackQueenside != 0 {
+		castling += "q"
+	}
+
+	if castling == "" {
+		return "-"
+	}
+	return castling
+}
+
+func (p *Position) turnString() string {
+	if p.Turn == White {
+		return "w"
+	}
+	return "b"
+}
+
+func (p *Position) FEN() string {
+	return fmt.Sprintf("%s %s %s %s %d %d", p.placement(), p.turnString(), p.castlingString(), p.EnPassant.String(), p.HalfmoveClock, p.FullmoveNumber)
+}
+
+// EPD returns the first four FEN fields, the en passant square is only
+// included when a capture on it is actually possible so that transposed
+// positions produce the same string.
+func (p *Position) EPD() string {
+	ep := NoSquare
+	if p.EnPassant != NoSquare {
+		for _, move := range p.LegalMoves() {
+			if move.To == p.EnPassant && p.Board[move.From].Type == Pawn {
+				ep = p.EnPassant
+				break
+			}
+		}
+	}
+
+	return fmt.Sprintf("%s %s %s %s", p.placement(), p.turnString(), p.castlingString(), ep.String())
+}
+
+func pieceRune(piece Piece) rune {
+	r := rune(" PNBRQK"[piece.Type])
+	if piece.Color == Black {
+		r += 'a' - 'A'
+	}
+	return r
+}
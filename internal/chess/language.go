@@ -0,0 +1,72 @@
+package chess
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Language holds the piece letters of a language indexed by PieceType,
+// pawns have no letter.
+type Language [7]string
+
+var English = Language{"", "", "N", "B", "R", "Q", "K"}
+
+var Languages = map[string]Language{
+	"en": English,
+	"cs": {"", "", "J", "S", "V", "D", "K"},
+	"da": {"", "", "S", "L", "T", "D", "K"},
+	"de": {"", "", "S", "L", "T", "D", "K"},
+	"es": {"", "", "C", "A", "T", "D", "R"},
+	"fr": {"", "", "C", "F", "T", "D", "R"},
+	"it": {"", "", "C", "A", "T", "D", "R"},
+	"nl": {"", "", "P", "L", "T", "D", "K"},
+	"no": {"", "", "S", "L", "T", "D", "K"},
+	"pl": {"", "", "S", "G", "W", "H", "K"},
+	"pt": {"", "", "C", "B", "T", "D", "R"},
+	"sv": {"", "", "S", "L", "T", "D", "K"},
+}
+
+func ParseLanguage(name string) (Language, error) {
+	language, exists := Languages[strings.ToLower(name)]
+	if !exists {
+		return Language{}, fmt.Errorf("unknown language: %s, expected one of %s", name,
+			strings.Join(slices.Sorted(maps.Keys(Languages)), ", "))
+	}
+
+	return language, nil
+}
+
+func (l Language) pieceType(letter rune) (PieceType, bool) {
+	for pieceType, pieceLetter := range l {
+		if pieceLetter == string(letter) {
+			return PieceType(pieceType), true
+		}
+	}
+
+	return NoPieceType, false
+}
+
+// TranslateSAN rewrites the piece letters of a move from one language to
+// another. Castling and letters unknown to the source language are kept.
+func TranslateSAN(san string, from Language, to Language) string {
+	if from == to || strings.Contains(san, "O-O") || strings.Contains(san, "0-0") {
+		return san
+	}
+
+	var sb strings.Builder
+
+	for _, r := range san {
+		if r >= 'A' && r <= 'Z' {
+			if pieceType, found := from.pieceType(r); found {
+				sb.WriteString(to[pieceType])
+				continue
+			}
+		}
+
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,347 @@
+package chess
+
+import "strings"
+
+type Move struct {
+	From      Square
+	To        Square
+	Promotion PieceType
+}
+
+var knightOffsets = [][2]int{{1, 2}, {2, 1}, {2, -1}, {1, -2}, {-1, -2}, {-2, -1}, {-2, 1}, {-1, 2}}
+var kingOffsets = [][2]int{{1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}, {0, -1}, {1, -1}}
+var bishopDirections = [][2]int{{1, 1}, {1, -1}, {-1, 1}, {-1, -1}}
+var rookDirections = [][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}}
+
+func offset(sq Square, df int, dr int) (Square, bool) {
+	file := sq.File() + df
+	rank := sq.Rank() + dr
+
+	if file < 0 || file > 7 || rank < 0 || rank > 7 {
+		return NoSquare, false
+	}
+
+	return NewSquare(file, rank), true
+}
+
+func (p *Position) IsAttacked(sq Square, by Color) bool {
+	for _, o := range knightOffsets {
+		if from, ok := offset(sq, o[0], o[1]); ok && p.Board[from] == (Piece{Knight, by}) {
+			return true
+		}
+	}
+
+	for _, o := range kingOffsets {
+		if from, ok := offset(sq, o[0], o[1]); ok && p.Board[from] == (Piece{King, by}) {
+			return true
+		}
+	}
+
+	// pawns attack diagonally forward so look backwards from the target
+	dr := -1
+	if by == Black {
+		dr = 1
+	}
+
+	for _, df := range []int{-1, 1} {
+		if from, ok := offset(sq, df, dr); ok && p.Board[from] == (Piece{Pawn, by}) {
+			return true
+		}
+	}
+
+	if p.slidingAttack(sq, by, bishopDirections, Bishop) {
+		return true
+	}
+
+	return p.slidingAttack(sq, by, rookDirections, Rook)
+}
+
+func (p *Position) slidingAttack(sq Square, by Color, directions [][2]int, slider PieceType) bool {
+	for _, d := range directions {
+		from := sq
+		for {
+			var ok bool
+			from, ok = offset(from, d[0], d[1])
+			if !ok {
+				break
+			}
+
+			piece := p.Board[from]
+			if piece.IsEmpty() {
+				continue
+			}
+
+			if piece.Color == by && (piece.Type == slider || piece.Type == Queen) {
+				return true
+			}
+			break
+		}
+	}
+
+	return false
+}
+
+func (p *Position) InCheck() bool {
+	king := p.KingSquare(p.Turn)
+	return king != NoSquare && p.IsAttacked(king, p.Turn.Other())
+}
+
+func (p *Position) IsCheckmate() bool {
+	return p.InCheck() && len(p.LegalMoves()) == 0
+}
+
+func (p *Position) IsStalemate() bool {
+	return !p.InCheck() && len(p.LegalMoves()) == 0
+}
+
+func (p *Position) LegalMoves() []Move {
+	var legal []Move
+
+	for _, move := range p.pseudoLegalMoves() {
+		next := p.Play(move)
+		king := next.KingSquare(p.Turn)
+
+		if king != NoSquare && !next.IsAttacked(king, next.Turn) {
+			legal = append(legal, move)
+		}
+	}
+
+	return legal
+}
+
+func (p *Position) pseudoLegalMoves() []Move {
+	var moves []Move
+
+	for from := Square(0); from < 64; from++ {
+		piece := p.Board[from]
+		if piece.IsEmpty() || piece.Color != p.Turn {
+			continue
+		}
+
+		switch piece.Type {
+		case Pawn:
+			moves = p.appendPawnMoves(moves, from)
+		case Knight:
+			moves = p.appendStepMoves(moves, from, knightOffsets)
+		case Bishop:
+			moves = p.appendSlidingMoves(moves, from, bishopDirections)
+		case Rook:
+			moves = p.appendSlidingMoves(moves, from, rookDirections)
+		case Queen:
+			moves = p.appendSlidingMoves(moves, from, bishopDirections)
+			moves = p.appendSlidingMoves(moves, from, rookDirections)
+		case King:
+			moves = p.appendStepMoves(moves, from, kingOffsets)
+			moves = p.appendCastlingMoves(moves, from)
+		}
+	}
+
+	return moves
+}
+
+func (p *Position) appendPawnMoves(moves []Move, from Square) []Move {
+	dr, startRank, lastRank := 1, 1, 7
+	if p.Turn == Black {
+		dr, startRank, lastRank = -1, 6, 0
+	}
+
+	add := func(to Square) {
+		if to.Rank() == lastRank {
+			for _, promotion := range []PieceType{Queen, Rook, Bishop, Knight} {
+				moves = append(moves, Move{From: from, To: to, Promotion: promotion})
+			}
+			return
+		}
+		moves = append(moves, Move{From: from, To: to})
+	}
+
+	if to, ok := offset(from, 0, dr); ok && p.Board[to].IsEmpty() {
+		add(to)
+
+		if from.Rank() == startRank {
+			if to2, ok := offset(to, 0, dr); ok && p.Board[to2].IsEmpty() {
+				add(to2)
+			}
+		}
+	}
+
+	for _, df := range []int{-1, 1} {
+		to, ok := offset(from, df, dr)
+		if !ok {
+			continue
+		}
+
+		target := p.Board[to]
+		if (!target.IsEmpty() && target.Color != p.Turn) || to == p.EnPassant {
+			add(to)
+		}
+	}
+
+	return moves
+}
+
+func (p *Position) appendStepMoves(moves []Move, from Square, offsets [][2]int) []Move {
+	for _, o := range offsets {
+		to, ok := offset(from, o[0], o[1])
+		if !ok {
+			continue
+		}
+
+		target := p.Board[to]
+		if target.IsEmpty() || target.Color != p.Turn {
+			moves = append(moves, Move{From: from, To: to})
+		}
+	}
+
+	return moves
+}
+
+func (p *Position) appendSlidingMoves(moves []Move, from Square, directions [][2]int) []Move {
+	for _, d := range directions {
+		to := from
+		for {
+			var ok bool
+			to, ok = offset(to, d[0], d[1])
+			if !ok {
+				break
+			}
+
+			target := p.Board[to]
+			if target.IsEmpty() {
+				moves = append(moves, Move{From: from, To: to})
+				continue
+			}
+
+			if target.Color != p.Turn {
+				moves = append(moves, Move{From: from, To: to})
+			}
+			break
+		}
+	}
+
+	return moves
+}
+
+func (p *Position) appendCastlingMoves(moves []Move, from Square) []Move {
+	rank := 0
+	kingside, queenside := WhiteKingside, WhiteQueenside
+	if p.Turn == Black {
+		rank = 7
+		kingside, queenside = BlackKingside, BlackQueenside
+	}
+
+	if from != NewSquare(4, rank) || p.IsAttacked(from, p.Turn.Other()) {
+		return moves
+	}
+
+	rook := Piece{Rook, p.Turn}
+
+	if p.Castling&kingside != 0 && p.Board[NewSquare(7, rank)] == rook &&
+		p.Board[NewSquare(5, rank)].IsEmpty() && p.Board[NewSquare(6, rank)].IsEmpty() &&
+		!p.IsAttacked(NewSquare(5, rank), p.Turn.Other()) {
+		moves = append(moves, Move{From: from, To: NewSquare(6, rank)})
+	}
+
+	if p.Castling&queenside != 0 && p.Board[NewSquare(0, rank)] == rook &&
+		p.Board[NewSquare(1, rank)].IsEmpty() && p.Board[NewSquare(2, rank)].IsEmpty() &&
+		p.Board[NewSquare(3, rank)].IsEmpty() && !p.IsAttacked(NewSquare(3, rank), p.Turn.Other()) {
+		moves = append(moves, Move{From: from, To: NewSquare(2, rank)})
+	}
+
+	return moves
+}
+
+func (p *Position) IsCastling(move Move) bool {
+	piece := p.Board[move.From]
+	return piece.Type == King && abs(move.To.File()-move.From.File()) == 2
+}
+
+func (p *Position) IsEnPassant(move Move) bool {
+	return p.Board[move.From].Type == Pawn && move.To == p.EnPassant && move.From.File() != move.To.File()
+}
+
+func (p *Position) IsCapture(move Move) bool {
+	target := p.Board[move.To]
+	return (!target.IsEmpty() && target.Color != p.Turn) || p.IsEnPassant(move)
+}
+
+// Play returns the position after the move, the move is not checked for
+// legality.
+func (p *Position) Play(move Move) *Position {
+	next := p.Clone()
+	piece := p.Board[move.From]
+
+	next.HalfmoveClock++
+	if piece.Type == Pawn || p.IsCapture(move) {
+		next.HalfmoveClock = 0
+	}
+
+	if p.IsEnPassant(move) {
+		next.Board[NewSquare(move.To.File(), move.From.Rank())] = NoPiece
+	}
+
+	if p.IsCastling(move) {
+		rank := move.From.Rank()
+		if move.To.File() == 6 {
+			next.Board[NewSquare(5, rank)] = next.Board[NewSquare(7, rank)]
+			next.Board[NewSquare(7, rank)] = NoPiece
+		} else {
+			next.Board[NewSquare(3, rank)] = next.Board[NewSquare(0, rank)]
+			next.Board[NewSquare(0, rank)] = NoPiece
+		}
+	}
+
+	next.Board[move.To] = piece
+	next.Board[move.From] = NoPiece
+
+	if move.Promotion != NoPieceType {
+		next.Board[move.To] = Piece{move.Promotion, piece.Color}
+	}
+
+	next.EnPassant = NoSquare
+	if piece.Type == Pawn && abs(move.To.Rank()-move.From.Rank()) == 2 {
+		next.EnPassant = NewSquare(move.From.File(), (move.From.Rank()+move.To.Rank())/2)
+	}
+
+	for _, sq := range []Square{move.From, move.To} {
+		switch sq {
+		case NewSquare(4, 0):
+			next.Castling &^= WhiteKingside | WhiteQueenside
+		case NewSquare(7, 0):
+			next.Castling &^= WhiteKingside
+		case NewSquare(0, 0):
+			next.Castling &^= WhiteQueenside
+		case NewSquare(4, 7):
+			next.Castling &^= BlackKingside | BlackQueenside
+		case NewSquare(7, 7):
+			next.Castling &^= BlackKingside
+		case NewSquare(0, 7):
+			next.Castling &^= BlackQueenside
+		}
+	}
+
+	if p.Turn == Black {
+		next.FullmoveNumber++
+	}
+	next.Turn = p.Turn.Other()
+
+	return next
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// UCI formats the move in the notation of the universal chess interface, the
+// squares followed by a lowercase promotion piece.
+func (m Move) UCI() string {
+	uci := m.From.String() + m.To.String()
+	if m.Promotion != NoPieceType {
+		uci += strings.ToLower(PieceLetter(m.Promotion))
+	}
+
+	return uci
+}
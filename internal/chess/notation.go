@@ -0,0 +1,62 @@
+package chess
+
+import (
+	"fmt"
+	"strings"
+)
+
+var figurines = map[PieceType]string{
+	Knight: "♘",
+	Bishop: "♗",
+	Rook:   "♖",
+	Queen:  "♕",
+	King:   "♔",
+}
+
+// LAN formats a legal move in long algebraic notation, both squares are
+// written with a dash or capture mark between them: Ng1-f3, e4xd5.
+func (p *Position) LAN(move Move) string {
+	if p.IsCastling(move) {
+		return p.san(move, PieceLetter) + p.checkSuffix(move)
+	}
+
+	var sb strings.Builder
+
+	if piece := p.Board[move.From]; piece.Type != Pawn {
+		sb.WriteString(PieceLetter(piece.Type))
+	}
+
+	sb.WriteString(move.From.String())
+
+	if p.IsCapture(move) {
+		sb.WriteString("x")
+	} else {
+		sb.WriteString("-")
+	}
+
+	sb.WriteString(move.To.String())
+
+	if move.Promotion != NoPieceType {
+		sb.WriteString("=")
+		sb.WriteString(PieceLetter(move.Promotion))
+	}
+
+	return sb.String() + p.checkSuffix(move)
+}
+
+// Figurine formats a legal move in standard algebraic notation with the
+// piece letters replaced by chess symbols.
+func (p *Position) Figurine(move Move) string {
+	return p.san(move, func(pieceType PieceType) string {
+		return figurines[pieceType]
+	}) + p.checkSuffix(move)
+}
+
+// Numbered writes a move of the side to move after its move number, "12. e4"
+// for white and "12... e5" for black.
+func (p *Position) Numbered(san string) string {
+	if p.Turn == White {
+		return fmt.Sprintf("%d. %s", p.FullmoveNumber, san)
+	}
+	return fmt.Sprintf("%d... %s", p.FullmoveNumber, san)
+}
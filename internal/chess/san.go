@@ -0,0 +1,222 @@
+package chess
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInvalidSAN    = errors.New("invalid move notation")
+	ErrIllegalMove   = errors.New("illegal move")
+	ErrAmbiguousMove = errors.New("ambiguous move")
+)
+
+var pieceLetters = map[byte]PieceType{
+	'N': Knight,
+	'B': Bishop,
+	'R': Rook,
+	'Q': Queen,
+	'K': King,
+}
+
+func PieceLetter(pieceType PieceType) string {
+	return string(" PNBRQK"[pieceType])
+}
+
+// ParseSAN resolves a move in standard algebraic notation against the
+// position. Common deviations such as 0-0, missing capture marks, long
+// algebraic notation and promotions without '=' are accepted.
+func (p *Position) ParseSAN(san string) (Move, error) {
+	candidates, err := p.MatchSAN(san)
+	if err != nil {
+		return Move{}, err
+	}
+
+	switch len(candidates) {
+	case 1:
+		return candidates[0], nil
+	case 0:
+		return Move{}, fmt.Errorf("%w: %s", ErrIllegalMove, san)
+	default:
+		return Move{}, fmt.Errorf("%w: %s", ErrAmbiguousMove, san)
+	}
+}
+
+// MatchSAN returns every legal move the notation can stand for, more than one
+// move means the notation is ambiguous.
+func (p *Position) MatchSAN(san string) ([]Move, error) {
+	str := strings.TrimRight(san, "+#!?")
+	str = strings.TrimSuffix(str, "e.p.")
+	str = strings.TrimSpace(str)
+
+	if str == "" {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSAN, san)
+	}
+
+	switch strings.ToUpper(strings.ReplaceAll(str, "0", "O")) {
+	case "O-O":
+		return p.findCastling(6), nil
+	case "O-O-O":
+		return p.findCastling(2), nil
+	}
+
+	pieceType := Pawn
+	if t, ok := pieceLetters[str[0]]; ok {
+		pieceType = t
+		str = str[1:]
+	}
+
+	promotion := NoPieceType
+	if idx := strings.IndexAny(str, "=/"); idx != -1 && idx < len(str)-1 {
+		t, ok := pieceLetters[str[idx+1]]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidSAN, san)
+		}
+		promotion = t
+		str = str[:idx]
+	} else if len(str) > 2 && pieceType == Pawn {
+		if t, ok := pieceLetters[str[len(str)-1]]; ok {
+			promotion = t
+			str = str[:len(str)-1]
+		}
+	}
+
+	if len(str) < 2 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSAN, san)
+	}
+
+	to, err := ParseSquare(str[len(str)-2:])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSAN, san)
+	}
+
+	disambiguation := strings.NewReplacer("x", "", "-", "", ":", "").Replace(str[:len(str)-2])
+	fromFile, fromRank := -1, -1
+
+	for i := range len(disambiguation) {
+		c := disambiguation[i]
+		switch {
+		case c >= 'a' && c <= 'h':
+			fromFile = int(c - 'a')
+		case c >= '1' && c <= '8':
+			fromRank = int(c - '1')
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrInvalidSAN, san)
+		}
+	}
+
+	matches := func(move Move) bool {
+		piece := p.Board[move.From]
+		if piece.Type != pieceType || move.To != to || move.Promotion != promotion {
+			return false
+		}
+		if fromFile != -1 && move.From.File() != fromFile {
+			return false
+		}
+		return fromRank == -1 || move.From.Rank() == fromRank
+	}
+
+	var candidates []Move
+	for _, move := range p.LegalMoves() {
+		if matches(move) {
+			candidates = append(candidates, move)
+		}
+	}
+
+	return candidates, nil
+}
+
+func (p *Position) findCastling(file int) []Move {
+	for _, move := range p.LegalMoves() {
+		if p.IsCastling(move) && move.To.File() == file {
+			return []Move{move}
+		}
+	}
+
+	return nil
+}
+
+// SAN formats a legal move in standard algebraic notation including the check
+// or checkmate suffix.
+func (p *Position) SAN(move Move) string {
+	return p.san(move, PieceLetter) + p.checkSuffix(move)
+}
+
+func (p *Position) san(move Move, letter func(PieceType) string) string {
+	piece := p.Board[move.From]
+
+	if p.IsCastling(move) {
+		if move.To.File() == 6 {
+			return "O-O"
+		}
+		return "O-O-O"
+	}
+
+	var sb strings.Builder
+
+	if piece.Type == Pawn {
+		if p.IsCapture(move) {
+			sb.WriteByte(byte('a' + move.From.File()))
+			sb.WriteString("x")
+		}
+	} else {
+		sb.WriteString(letter(piece.Type))
+		sb.WriteString(p.disambiguation(move))
+
+		if p.IsCapture(move) {
+			sb.WriteString("x")
+		}
+	}
+
+	sb.WriteString(move.To.String())
+
+	if move.Promotion != NoPieceType {
+		sb.WriteString("=")
+		sb.WriteString(letter(move.Promotion))
+	}
+
+	return sb.String()
+}
+
+func (p *Position) disambiguation(move Move) string {
+	piece := p.Board[move.From]
+	sameFile, sameRank, others := false, false, false
+
+	for _, other := range p.LegalMoves() {
+		if other.From == move.From || other.To != move.To || p.Board[other.From] != piece {
+			continue
+		}
+
+		others = true
+		if other.From.File() == move.From.File() {
+			sameFile = true
+		}
+		if other.From.Rank() == move.From.Rank() {
+			sameRank = true
+		}
+	}
+
+	switch {
+	case !others:
+		return ""
+	case !sameFile:
+		return string(rune('a' + move.From.File()))
+	case !sameRank:
+		return string(rune('1' + move.From.Rank()))
+	default:
+		return move.From.String()
+	}
+}
+
+func (p *Position) checkSuffix(move Move) string {
+	next := p.Play(move)
+	if !next.InCheck() {
+		return ""
+	}
+
+	if len(next.LegalMoves()) == 0 {
+		return "#"
+	}
+	return "+"
+}
@@ -0,0 +1,372 @@
+package dedup
+
+import (
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/gavink97/pgn-tools/internal/parser"
+	"github.com/gavink97/pgn-tools/internal/types"
+)
+
+const (
+	// players, date, result and a hash of the moves must all match
+	Players = "players"
+	// the moves must be identical and the headers roughly agree, games
+	// without moves are matched by the players key
+	Moves = "moves"
+)
+
+type entry struct {
+	game     *types.Game
+	source   string
+	priority int
+}
+
+type Dropped struct {
+	Game       *types.Game
+	Source     string
+	Kept       *types.Game
+	KeptSource string
+}
+
+// Deduplicator keeps one game out of every set of duplicates, the most
+// complete game of a set wins and earlier games win ties. With Reconcile set
+// the duplicates are instead merged field by field into one game.
+type Deduplicator struct {
+	Mode      string
+	Reconcile bool
+	Dropped   []Dropped
+	entries   []*entry
+	byKey     map[string][]int
+	sources   map[string]int
+}
+
+func New(mode string) (*Deduplicator, error) {
+	mode = strings.ToLower(mode)
+
+	if mode != Players && mode != Moves {
+		return nil, fmt.Errorf("unknown duplicate key: %s, expected %s or %s", mode, Players, Moves)
+	}
+
+	return &Deduplicator{
+		Mode:    mode,
+		byKey:   map[string][]int{},
+		sources: map[string]int{},
+	}, nil
+}
+
+// NewReconciler returns a deduplicator that reconciles duplicates. Without a
+// mode the moves key is used, the players key requires the exact date and
+// result so copies that differ in them would never be reconciled.
+func NewReconciler(mode string) (*Deduplicator, error) {
+	if mode == "" {
+		mode = Moves
+	}
+
+	d, err := New(mode)
+	if err != nil {
+		return nil, err
+	}
+
+	d.Reconcile = true
+	return d, nil
+}
+
+// Add adds a game read from source, sources added first have priority over
+// later sources when reconciling.
+func (d *Deduplicator) Add(game *types.Game, source string) {
+	priority, exists := d.sources[source]
+	if !exists {
+		priority = len(d.sources)
+		d.sources[source] = priority
+	}
+
+	key, byMoves := d.key(game)
+	candidate := &entry{game: game, source: source, priority: priority}
+
+	for _, idx := range d.byKey[key] {
+		existing := d.entries[idx]
+
+		if byMoves && !fuzzyHeaderMatch(existing.game, game) {
+			continue
+		}
+
+		if d.Reconcile {
+			reconciled := reconcile(existing, candidate)
+			d.entries[idx] = reconciled
+
+			d.Dropped = append(d.Dropped, Dropped{
+				Game:       game,
+				Source:     source,
+				Kept:       reconciled.game,
+				KeptSource: reconciled.source,
+			})
+			return
+		}
+
+		kept, dropped := existing, candidate
+		if Completeness(game) > Completeness(existing.game) {
+			kept, dropped = candidate, existing
+			d.entries[idx] = candidate
+		}
+
+		d.Dropped = append(d.Dropped, Dropped{
+			Game:       dropped.game,
+			Source:     dropped.source,
+			Kept:       kept.game,
+			KeptSource: kept.source,
+		})
+		return
+	}
+
+	d.byKey[key] = append(d.byKey[key], len(d.entries))
+	d.entries = append(d.entries, candidate)
+}
+
+// Games returns the kept games in the order they were first seen.
+func (d *Deduplicator) Games() []*types.Game {
+	games := make([]*types.Game, len(d.entries))
+	for i, e := range d.entries {
+		games[i] = e.game
+	}
+
+	return games
+}
+
+// key returns the key duplicates share and whether it is the moves key.
+func (d *Deduplicator) key(game *types.Game) (string, bool) {
+	moves := parser.ParseMoves(game.Game)
+	hash := fmt.Sprintf("%x", movesHash(moves))
+
+	// every game without moves has the same moves hash
+	if d.Mode == Moves && len(moves) > 0 {
+		return hash, true
+	}
+
+	return strings.Join([]string{
+		normalizeName(game.White),
+		normalizeName(game.Black),
+		game.Date,
+		game.Result,
+		hash,
+	}, "|"), false
+}
+
+// MovesHash hashes the main line moves ignoring check marks, annotations and
+// castling written with zeros.
+func MovesHash(game *types.Game) uint64 {
+	return movesHash(parser.ParseMoves(game.Game))
+}
+
+func movesHash(moves []string) uint64 {
+	h := fnv.New64a()
+
+	for _, move := range moves {
+		move = strings.TrimRight(move, "+#")
+		move = strings.ReplaceAll(move, "0", "O")
+		_, _ = h.Write([]byte(move))
+		_, _ = h.Write([]byte{' '})
+	}
+
+	return h.Sum64()
+}
+
+// fuzzyHeaderMatch compares surnames and years, an unknown value on either
+// side is treated as a match.
+func fuzzyHeaderMatch(a *types.Game, b *types.Game) bool {
+	if !fuzzyEqual(surname(a.White), surname(b.White)) || !fuzzyEqual(surname(a.Black), surname(b.Black)) {
+		return false
+	}
+
+	return fuzzyEqual(year(a.Date), year(b.Date))
+}
+
+func fuzzyEqual(a string, b string) bool {
+	return a == "" || b == "" || a == b
+}
+
+func surname(name string) string {
+	last, _, _ := strings.Cut(name, ",")
+	if !strings.Contains(name, ",") {
+		fields := strings.Fields(name)
+		if len(fields) > 0 {
+			last = fields[len(fields)-1]
+		}
+	}
+
+	return normalizeName(last)
+}
+
+func year(date string) string {
+	if len(date) < 4 || strings.Contains(date[:4], "?") {
+		return ""
+	}
+
+	return date[:4]
+}
+
+func normalizeName(name string) string {
+	name = strings.ToLower(name)
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == ',' {
+			return r
+		}
+		return -1
+	}, name)
+
+	if name == "?" {
+		return ""
+	}
+
+	return name
+}
+
+// Completeness scores how much information a game holds, every known header
+// counts once and annotated movetext adds a point per comment or variation.
+func Completeness(game *types.Game) int {
+	score := 0
+
+	for _, value := range []string{game.Event, game.Site, game.Date, game.Round, game.White,
+		game.Black, game.Result, game.ECO, game.EventDate, game.Source} {
+		if value != "" && !strings.Contains(value, "?") {
+			score++
+		}
+	}
+
+	if game.WhiteElo > 0 {
+		score++
+	}
+
+	if game.BlackElo > 0 {
+		score++
+	}
+
+	for _, token := range parser.ParseMovetext(game.Game) {
+		if token.Kind == parser.CommentToken || token.Kind == parser.VariationStartToken {
+			score++
+		}
+	}
+
+	return score
+}
+
+// reconcile merges two duplicates field by field. Known values win over
+// unknown ones, more precise dates win over less precise dates and annotated
+// movetext wins over bare movetext, anything else is taken from the source
+// with the higher priority.
+func reconcile(a *entry, b *entry) *entry {
+	primary, secondary := a, b
+	if b.priority < a.priority {
+		primary, secondary = b, a
+	}
+
+	game := *primary.game
+	other := secondary.game
+
+	for _, field := range []struct {
+		value *string
+		other string
+	}{
+		{&game.Event, other.Event},
+		{&game.Site, other.Site},
+		{&game.Round, other.Round},
+		{&game.White, other.White},
+		{&game.Black, other.Black},
+		{&game.ECO, other.ECO},
+		{&game.Opening, other.Opening},
+		{&game.Variation, other.Variation},
+		{&game.Source, other.Source},
+		{&game.TimeControl, other.TimeControl},
+		{&game.FEN, other.FEN},
+	} {
+		if isUnknown(*field.value) && !isUnknown(field.other) {
+			*field.value = field.other
+		}
+	}
+
+	if (game.Result == "" || game.Result == "*") && other.Result != "" {
+		game.Result = other.Result
+	}
+
+	if datePrecision(other.Date) > datePrecision(game.Date) {
+		game.Date = other.Date
+	}
+
+	if datePrecision(other.EventDate) > datePrecision(game.EventDate) {
+		game.EventDate = other.EventDate
+	}
+
+	if game.WhiteElo <= 0 && other.WhiteElo > 0 {
+		game.WhiteElo = other.WhiteElo
+	}
+
+	if game.BlackElo <= 0 && other.BlackElo > 0 {
+		game.BlackElo = other.BlackElo
+	}
+
+	if annotations(other) > annotations(&game) {
+		game.Game = other.Game
+	}
+
+	// tags only the other copy has are added after the tags of the game
+	game.ExtraTags = slices.Clone(game.ExtraTags)
+	for _, t := range other.ExtraTags {
+		if !slices.ContainsFunc(game.ExtraTags, func(own types.Tag) bool { return own.Name == t.Name }) {
+			game.ExtraTags = append(game.ExtraTags, t)
+		}
+	}
+
+	return &entry{game: &game, source: primary.source, priority: primary.priority}
+}
+
+func isUnknown(value string) bool {
+	return value == "" || value == "?"
+}
+
+// datePrecision counts the known characters of a pgn date
+func datePrecision(date string) int {
+	precision := 0
+	for _, c := range date {
+		if c >= '0' && c <= '9' {
+			precision++
+		}
+	}
+
+	return precision
+}
+
+func annotations(game *types.Game) int {
+	count := 0
+	for _, token := range parser.ParseMovetext(game.Game) {
+		switch token.Kind {
+		case parser.CommentToken, parser.VariationStartToken, parser.NAGToken:
+			count++
+		}
+	}
+
+	return count
+}
+
+// Report describes every dropped game and the game that was kept instead.
+func (d *Deduplicator) Report() string {
+	var sb strings.Builder
+
+	action := "dropped"
+	if d.Reconcile {
+		action = "merged"
+	}
+
+	for _, dropped := range d.Dropped {
+		fmt.Fprintf(&sb, "%s %s from %s, duplicate of %s from %s\n",
+			action, describe(dropped.Game), dropped.Source, describe(dropped.Kept), dropped.KeptSource)
+	}
+
+	return sb.String()
+}
+
+func describe(game *types.Game) string {
+	return fmt.Sprintf("%s - %s, %s, %s, %s", game.White, game.Black, game.Event, game.Date, game.Result)
+}
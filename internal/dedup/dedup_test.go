@@ -0,0 +1,150 @@
+package dedup
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gavink97/pgn-tools/internal/types"
+)
+
+var sampleGames = []*types.Game{
+	{
+		Event:  "Tata Steel Masters",
+		Date:   "2023.01.14",
+		White:  "Carlsen, Magnus",
+		Black:  "Giri, Anish",
+		Result: "1/2-1/2",
+		Game:   "1.e4 e5 2.Nf3 Nc6 3.Bb5 Nf6 1/2-1/2",
+	},
+	{
+		Event:    "Tata Steel Masters",
+		Site:     "Wijk aan Zee NED",
+		Date:     "2023.01.14",
+		Round:    "1",
+		White:    "Carlsen, Magnus",
+		Black:    "Giri, Anish",
+		Result:   "1/2-1/2",
+		WhiteElo: 2859,
+		BlackElo: 2764,
+		Game:     "1. e4 e5 2. Nf3 Nc6 3. Bb5 Nf6 1/2-1/2",
+	},
+	{
+		Event:  "Wijk aan Zee",
+		Date:   "2023.??.??",
+		White:  "Magnus Carlsen",
+		Black:  "Anish Giri",
+		Result: "1/2-1/2",
+		Game:   "1.e4 e5 2.Nf3 Nc6 3.Bb5 Nf6 1/2-1/2",
+	},
+	{
+		Event:  "Tata Steel Masters",
+		Date:   "2023.01.14",
+		White:  "Carlsen, Magnus",
+		Black:  "Giri, Anish",
+		Result: "1/2-1/2",
+		Game:   "1.e4 e5 2.Nf3 Nc6 3.Bc4 Nf6 1/2-1/2",
+	},
+}
+
+func TestDeduplicator(t *testing.T) {
+	samples := map[string]int{
+		Players: 1,
+		Moves:   2,
+	}
+
+	for mode, expectedDropped := range samples {
+		deduplicator, err := New(mode)
+		if err != nil {
+			t.Fatalf("An error occured creating deduplicator: %v", err)
+		}
+
+		for _, game := range sampleGames {
+			deduplicator.Add(game, "sample.pgn")
+		}
+
+		if len(deduplicator.Dropped) != expectedDropped {
+			t.Errorf("Incorrect Result for %s: \nresult: %v \nexpected: %v", mode, len(deduplicator.Dropped), expectedDropped)
+		}
+
+		games := deduplicator.Games()
+		if games[0] != sampleGames[1] {
+			t.Errorf("Incorrect Result for %s: \nresult: %v \nexpected: %v", mode, games[0], sampleGames[1])
+		}
+	}
+
+	_, err := New("event")
+	if err == nil {
+		t.Errorf("Expected an error for an unknown duplicate key")
+	}
+}
+
+func TestDeduplicatorWithoutMoves(t *testing.T) {
+	deduplicator, err := New(Moves)
+	if err != nil {
+		t.Fatalf("An error occured creating deduplicator: %v", err)
+	}
+
+	games := []*types.Game{
+		{Date: "2023.01.14", White: "Carlsen, Magnus", Black: "Giri, Anish", Result: "1-0", Game: "1-0"},
+		{Date: "2023.01.15", White: "Carlsen, Magnus", Black: "Giri, Anish", Result: "1-0", Game: "1-0"},
+		{Date: "2023.01.14", White: "Carlsen, Magnus", Black: "Giri, Anish", Result: "1-0", Game: "1-0"},
+	}
+
+	for _, game := range games {
+		deduplicator.Add(game, "games.pgn")
+	}
+
+	if len(deduplicator.Games()) != 2 || len(deduplicator.Dropped) != 1 {
+		t.Errorf("Incorrect Result: \nresult: %v kept %v dropped \nexpected: %v kept %v dropped",
+			len(deduplicator.Games()), len(deduplicator.Dropped), 2, 1)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	// reconcile keys by moves unless a key is given
+	deduplicator, err := NewReconciler("")
+	if err != nil {
+		t.Fatalf("An error occured creating deduplicator: %v", err)
+	}
+
+	if deduplicator.Mode != Moves || !deduplicator.Reconcile {
+		t.Errorf("Incorrect Result: \nresult: %v %v \nexpected: %v %v", deduplicator.Mode, deduplicator.Reconcile, Moves, true)
+	}
+
+	annotated := &types.Game{
+		Event:     "Tata Steel Masters",
+		Date:      "2023.??.??",
+		Round:     "?",
+		White:     "Carlsen, Magnus",
+		Black:     "Giri, Anish",
+		Result:    "1/2-1/2",
+		ExtraTags: []types.Tag{{Name: "Annotator", Value: "Giri, Anish"}},
+		Game:      "1.e4 {best by test} e5 2.Nf3 Nc6 3.Bb5 Nf6 1/2-1/2",
+	}
+
+	deduplicator.Add(annotated, "twic.pgn")
+	deduplicator.Add(sampleGames[1], "master.pgn")
+
+	expected := types.Game{
+		Event:     "Tata Steel Masters",
+		Site:      "Wijk aan Zee NED",
+		Date:      "2023.01.14",
+		Round:     "1",
+		White:     "Carlsen, Magnus",
+		Black:     "Giri, Anish",
+		Result:    "1/2-1/2",
+		WhiteElo:  2859,
+		BlackElo:  2764,
+		ExtraTags: []types.Tag{{Name: "Annotator", Value: "Giri, Anish"}},
+		Game:      "1.e4 {best by test} e5 2.Nf3 Nc6 3.Bb5 Nf6 1/2-1/2",
+	}
+
+	games := deduplicator.Games()
+	if len(games) != 1 {
+		t.Fatalf("Incorrect Result: \nresult: %v \nexpected: %v", len(games), 1)
+	}
+
+	if !reflect.DeepEqual(*games[0], expected) {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", *games[0], expected)
+	}
+}
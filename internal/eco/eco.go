@@ -0,0 +1,123 @@
+package eco
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gavink97/pgn-tools/internal/chess"
+)
+
+//go:embed eco.tsv
+var ecoTable string
+
+type Opening struct {
+	ECO       string
+	Opening   string
+	Variation string
+}
+
+type classifier struct {
+	positions map[string]Opening
+	maxPlies  int
+}
+
+var (
+	loadOnce sync.Once
+	loaded   *classifier
+	loadErr  error
+)
+
+func load() (*classifier, error) {
+	loadOnce.Do(func() {
+		loaded, loadErr = parseTable(ecoTable)
+	})
+
+	return loaded, loadErr
+}
+
+func parseTable(table string) (*classifier, error) {
+	c := &classifier{positions: map[string]Opening{}}
+
+	for i, line := range strings.Split(strings.TrimSpace(table), "\n") {
+		if i == 0 {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid eco table line %d: %s", i+1, line)
+		}
+
+		name, variation, _ := strings.Cut(fields[1], ": ")
+		opening := Opening{ECO: fields[0], Opening: name, Variation: variation}
+
+		pos := chess.StartingPosition()
+		plies := 0
+
+		for _, san := range strings.Fields(fields[2]) {
+			if strings.HasSuffix(san, ".") {
+				continue
+			}
+
+			move, err := pos.ParseSAN(san)
+			if err != nil {
+				return nil, fmt.Errorf("invalid eco table line %d: %v", i+1, err)
+			}
+
+			pos = pos.Play(move)
+			plies++
+		}
+
+		// the first line reaching a position names it, later transpositions
+		// do not override it
+		key := pos.EPD()
+		if _, exists := c.positions[key]; !exists {
+			c.positions[key] = opening
+		}
+
+		c.maxPlies = max(c.maxPlies, plies)
+	}
+
+	return c, nil
+}
+
+// Classify replays the moves from the starting position and returns the
+// opening of the deepest position found in the table. Games starting from a
+// custom FEN are not classified.
+func Classify(fen string, moves []string) (Opening, bool) {
+	if fen != "" {
+		return Opening{}, false
+	}
+
+	c, err := load()
+	if err != nil {
+		return Opening{}, false
+	}
+
+	var opening Opening
+	found := false
+
+	pos := chess.StartingPosition()
+
+	for i, san := range moves {
+		if i >= c.maxPlies {
+			break
+		}
+
+		move, err := pos.ParseSAN(san)
+		if err != nil {
+			break
+		}
+
+		pos = pos.Play(move)
+
+		if o, ok := c.positions[pos.EPD()]; ok {
+			opening = o
+			found = true
+		}
+	}
+
+	return opening, found
+}
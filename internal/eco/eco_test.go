@@ -0,0 +1,54 @@
+package eco
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTable(t *testing.T) {
+	_, err := parseTable(ecoTable)
+	if err != nil {
+		t.Errorf("An error occured parsing eco table: %v", err)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	samples := []struct {
+		moves    string
+		expected Opening
+	}{
+		{
+			moves:    "e4 e5 Nf3 Nc6 Bb5 Nf6 d3 Bc5 Bxc6 dxc6",
+			expected: Opening{ECO: "C65", Opening: "Ruy Lopez", Variation: "Berlin Defense"},
+		},
+		{
+			moves:    "e4 c5 Nf3 d6 d4 cxd4 Nxd4 Nf6 Nc3 a6 Be3 e5",
+			expected: Opening{ECO: "B90", Opening: "Sicilian Defense", Variation: "Najdorf Variation, English Attack"},
+		},
+		{
+			// reaches the Semi-Slav by transposition from the Queen's Gambit Declined
+			moves:    "d4 d5 c4 e6 Nc3 Nf6 Nf3 c6",
+			expected: Opening{ECO: "D43", Opening: "Semi-Slav Defense"},
+		},
+		{
+			moves:    "Nf3 d5 d4 Nf6 Bf4 e6",
+			expected: Opening{ECO: "D02", Opening: "Queen's Pawn Game", Variation: "London System"},
+		},
+	}
+
+	for _, sample := range samples {
+		result, found := Classify("", strings.Fields(sample.moves))
+		if !found {
+			t.Errorf("Unable to classify: %s", sample.moves)
+		}
+
+		if result != sample.expected {
+			t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", result, sample.expected)
+		}
+	}
+
+	_, found := Classify("4k3/8/8/8/8/8/4K3/R6R w - - 0 1", []string{"Ra2"})
+	if found {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", found, false)
+	}
+}
@@ -0,0 +1,365 @@
+package fix
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/gavink97/pgn-tools/internal/chess"
+	"github.com/gavink97/pgn-tools/internal/parser"
+	"github.com/gavink97/pgn-tools/internal/types"
+)
+
+// UnknownDate is the date of a game whose date is not known.
+const UnknownDate = "????.??.??"
+
+var resultVariants = map[string]string{
+	"1-0":     "1-0",
+	"1:0":     "1-0",
+	"0-1":     "0-1",
+	"0:1":     "0-1",
+	"1/2-1/2": "1/2-1/2",
+	"1/2:1/2": "1/2-1/2",
+	"1/2":     "1/2-1/2",
+	"½-½":     "1/2-1/2",
+	"½:½":     "1/2-1/2",
+	"½":       "1/2-1/2",
+	"0.5-0.5": "1/2-1/2",
+	"0,5-0,5": "1/2-1/2",
+	"draw":    "1/2-1/2",
+	"*":       "*",
+}
+
+// NormalizeResult maps the ways results are written to the pgn result
+// tokens, it reports whether the result was recognized.
+func NormalizeResult(result string) (string, bool) {
+	key := strings.NewReplacer("–", "-", "—", "-", " ", "").Replace(strings.ToLower(strings.TrimSpace(result)))
+	normalized, exists := resultVariants[key]
+	return normalized, exists
+}
+
+// Game repairs a game in place and returns a description of every change.
+// Moves are replayed and rewritten in standard algebraic notation, which
+// adds check marks, spells castling with O and fixes the disambiguation.
+// Move numbers are written by the pgn writer.
+func Game(game *types.Game) []string {
+	var changes []string
+
+	changes = append(changes, stripControl(game)...)
+	changes = append(changes, fixMovetext(game)...)
+	changes = append(changes, fixResult(game)...)
+	changes = append(changes, addRoster(game)...)
+
+	return changes
+}
+
+func stripControl(game *types.Game) []string {
+	var changes []string
+
+	fields := []struct {
+		name  string
+		value *string
+	}{
+		{"Event", &game.Event},
+		{"Site", &game.Site},
+		{"Date", &game.Date},
+		{"Round", &game.Round},
+		{"White", &game.White},
+		{"Black", &game.Black},
+		{"Result", &game.Result},
+		{"ECO", &game.ECO},
+		{"Opening", &game.Opening},
+		{"Variation", &game.Variation},
+		{"EventDate", &game.EventDate},
+		{"Source", &game.Source},
+		{"TimeControl", &game.TimeControl},
+		{"FEN", &game.FEN},
+		{"movetext", &game.Game},
+	}
+
+	for i := range game.ExtraTags {
+		fields = append(fields, struct {
+			name  string
+			value *string
+		}{game.ExtraTags[i].Name, &game.ExtraTags[i].Value})
+	}
+
+	for _, field := range fields {
+		stripped := strings.Map(func(r rune) rune {
+			switch {
+			case r == '\t' || r == '\n' || r == '\r':
+				return ' '
+			case unicode.IsControl(r):
+				return -1
+			default:
+				return r
+			}
+		}, *field.value)
+
+		if stripped != *field.value {
+			changes = append(changes, fmt.Sprintf("stripped control characters from %s", field.name))
+			*field.value = stripped
+		}
+	}
+
+	return changes
+}
+
+func addRoster(game *types.Game) []string {
+	var changes []string
+
+	roster := []struct {
+		name    string
+		value   *string
+		unknown string
+	}{
+		{"Event", &game.Event, "?"},
+		{"Site", &game.Site, "?"},
+		{"Date", &game.Date, UnknownDate},
+		{"Round", &game.Round, "?"},
+		{"White", &game.White, "?"},
+		{"Black", &game.Black, "?"},
+		{"Result", &game.Result, "*"},
+	}
+
+	for _, tag := range roster {
+		if strings.TrimSpace(*tag.value) == "" {
+			*tag.value = tag.unknown
+			changes = append(changes, fmt.Sprintf("added missing tag %s %q", tag.name, tag.unknown))
+		}
+	}
+
+	return changes
+}
+
+// fixResult makes the Result tag agree with the termination marker, the
+// marker wins when the tag is unknown or unreadable and the tag wins when the
+// marker is *. Two different decisive results are left and reported.
+func fixResult(game *types.Game) []string {
+	var changes []string
+	marker := termination(game.Game)
+
+	result, valid := NormalizeResult(game.Result)
+	switch {
+	case valid && result != game.Result:
+		changes = append(changes, fmt.Sprintf("normalized Result %q to %q", game.Result, result))
+		game.Result = result
+	case !valid && marker != "":
+		changes = append(changes, fmt.Sprintf("set Result %q from the termination marker %s", game.Result, marker))
+		game.Result = marker
+	}
+
+	switch {
+	case marker == "":
+		if _, valid := NormalizeResult(game.Result); !valid {
+			game.Result = "*"
+		}
+		game.Game = strings.TrimSpace(game.Game + " " + game.Result)
+		changes = append(changes, fmt.Sprintf("added termination marker %s", game.Result))
+	case !valid || marker == game.Result:
+	case game.Result == "*":
+		changes = append(changes, fmt.Sprintf("set Result %q from the termination marker %s", game.Result, marker))
+		game.Result = marker
+	case marker == "*" && strings.HasSuffix(game.Game, marker):
+		game.Game = strings.TrimSuffix(game.Game, marker) + game.Result
+		changes = append(changes, fmt.Sprintf("set termination marker %s from the Result tag %q", marker, game.Result))
+	default:
+		changes = append(changes, fmt.Sprintf("left Result %q that contradicts the termination marker %s", game.Result, marker))
+	}
+
+	return changes
+}
+
+func termination(movetext string) string {
+	depth := 0
+	marker := ""
+
+	for _, token := range parser.ParseMovetext(movetext) {
+		switch token.Kind {
+		case parser.VariationStartToken:
+			depth++
+		case parser.VariationEndToken:
+			depth--
+		case parser.ResultToken:
+			if depth == 0 {
+				marker = token.Text
+			}
+		}
+	}
+
+	return marker
+}
+
+type line struct {
+	pos  *chess.Position
+	prev *chess.Position
+}
+
+// fixMovetext replays the movetext including variations and rewrites every
+// move in standard algebraic notation. Once a move can not be replayed the
+// rest of its line is kept as written.
+func fixMovetext(game *types.Game) []string {
+	var changes []string
+
+	pos := chess.StartingPosition()
+	if game.FEN != "" {
+		p, err := chess.ParseFEN(game.FEN)
+		if err != nil {
+			return []string{fmt.Sprintf("left the moves unchanged, bad FEN: %v", err)}
+		}
+		pos = p
+	}
+
+	tokens := parser.ParseMovetext(game.Game)
+	current := line{pos: pos}
+	var stack []line
+	var words []string
+
+	numbered := false
+	missingNumbers := 0
+
+	for i, token := range tokens {
+		switch token.Kind {
+		case parser.MoveNumberToken:
+			numbered = true
+			continue
+
+		case parser.CommentToken:
+			// a comment between the move number and the move keeps the number
+			words = append(words, "{"+token.Text+"}")
+			continue
+
+		case parser.NAGToken:
+			words = append(words, token.Text)
+			continue
+
+		case parser.VariationStartToken:
+			stack = append(stack, current)
+			current = line{pos: current.prev}
+			words = append(words, "(")
+
+		case parser.VariationEndToken:
+			if len(stack) > 0 {
+				current = stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+			}
+			words = append(words, ")")
+
+		case parser.ResultToken:
+			words = append(words, token.Text)
+
+		case parser.MoveToken:
+			if result, isResult := NormalizeResult(token.Text); isResult {
+				changes = append(changes, fmt.Sprintf("normalized termination marker %s to %s", token.Text, result))
+				words = append(words, result)
+				continue
+			}
+
+			if current.pos == nil {
+				words = append(words, token.Text)
+				continue
+			}
+
+			if current.pos.Turn == chess.White && !numbered {
+				missingNumbers++
+			}
+
+			move, message := resolve(current.pos, token.Text, restOfLine(tokens, i))
+			if message != "" {
+				changes = append(changes, message)
+			}
+
+			if move == nil {
+				words = append(words, token.Text)
+				current = line{}
+				continue
+			}
+
+			san := current.pos.SAN(*move)
+			if san != token.Text && message == "" {
+				changes = append(changes, fmt.Sprintf("rewrote %s as %s", current.pos.Numbered(token.Text), san))
+			}
+
+			words = append(words, san)
+			current = line{pos: current.pos.Play(*move), prev: current.pos}
+		}
+
+		numbered = false
+	}
+
+	if missingNumbers > 0 {
+		changes = append(changes, fmt.Sprintf("added %d move numbers", missingNumbers))
+	}
+
+	game.Game = strings.Join(words, " ")
+	return changes
+}
+
+// resolve finds the move meant by the notation. An under-disambiguated move
+// is resolved when only one of its candidates lets the rest of the line be
+// replayed.
+func resolve(pos *chess.Position, san string, rest []string) (*chess.Move, string) {
+	number := pos.Numbered(san)
+
+	candidates, err := pos.MatchSAN(san)
+	if err != nil {
+		return nil, fmt.Sprintf("left invalid move %s and the rest of its line unchanged", number)
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, fmt.Sprintf("left illegal move %s and the rest of its line unchanged", number)
+	case 1:
+		return &candidates[0], ""
+	}
+
+	var playable []chess.Move
+	for _, candidate := range candidates {
+		if replays(pos.Play(candidate), rest) {
+			playable = append(playable, candidate)
+		}
+	}
+
+	if len(playable) != 1 {
+		return nil, fmt.Sprintf("left ambiguous move %s and the rest of its line unchanged", number)
+	}
+
+	return &playable[0], fmt.Sprintf("resolved ambiguous move %s as %s", number, pos.SAN(playable[0]))
+}
+
+func replays(pos *chess.Position, moves []string) bool {
+	for _, san := range moves {
+		move, err := pos.ParseSAN(san)
+		if err != nil {
+			return false
+		}
+		pos = pos.Play(move)
+	}
+
+	return true
+}
+
+// restOfLine returns the moves following the token at index in the same
+// line, variations in between are skipped.
+func restOfLine(tokens []parser.Token, index int) []string {
+	var moves []string
+	depth := 0
+
+	for _, token := range tokens[index+1:] {
+		switch token.Kind {
+		case parser.VariationStartToken:
+			depth++
+		case parser.VariationEndToken:
+			if depth == 0 {
+				return moves
+			}
+			depth--
+		case parser.MoveToken:
+			if _, isResult := NormalizeResult(token.Text); !isResult && depth == 0 {
+				moves = append(moves, token.Text)
+			}
+		}
+	}
+
+	return moves
+}
@@ -0,0 +1,182 @@
+package fix
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gavink97/pgn-tools/internal/types"
+)
+
+func TestGame(t *testing.T) {
+	game := &types.Game{
+		Event:  "Broken\x00 Open",
+		White:  "A",
+		Black:  "B",
+		Result: "1:0",
+		ExtraTags: []types.Tag{
+			{Name: "Annotator", Value: "C"}, {Name: "PlyCount", Value: "15"}, {Name: "Termination", Value: "normal\x07"},
+		},
+		Game: "e4 e5 Nf3 Nc6 Bc4 Nf6 Nfg5 d5 exd5 Nxd5 Nxf7 Kxf7 Qf3 Ke6 0-0 1:0",
+	}
+
+	changes := Game(game)
+
+	expected := &types.Game{
+		Event:  "Broken Open",
+		Site:   "?",
+		Date:   UnknownDate,
+		Round:  "?",
+		White:  "A",
+		Black:  "B",
+		Result: "1-0",
+		ExtraTags: []types.Tag{
+			{Name: "Annotator", Value: "C"}, {Name: "PlyCount", Value: "15"}, {Name: "Termination", Value: "normal"},
+		},
+		Game: "e4 e5 Nf3 Nc6 Bc4 Nf6 Ng5 d5 exd5 Nxd5 Nxf7 Kxf7 Qf3+ Ke6 O-O 1-0",
+	}
+
+	if !reflect.DeepEqual(game, expected) {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", game, expected)
+	}
+
+	expectedChanges := []string{
+		"stripped control characters from Event",
+		"stripped control characters from Termination",
+		"rewrote 4. Nfg5 as Ng5",
+		"rewrote 7. Qf3 as Qf3+",
+		"rewrote 8. 0-0 as O-O",
+		"normalized termination marker 1:0 to 1-0",
+		"added 8 move numbers",
+		`normalized Result "1:0" to "1-0"`,
+		`added missing tag Site "?"`,
+		`added missing tag Date "????.??.??"`,
+		`added missing tag Round "?"`,
+	}
+
+	if !reflect.DeepEqual(changes, expectedChanges) {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", changes, expectedChanges)
+	}
+}
+
+func TestGameAmbiguous(t *testing.T) {
+	samples := []struct {
+		movetext string
+		expected string
+		change   string
+	}{
+		{
+			// only the a1 rook leaves the h1 rook to reach h7
+			movetext: "1.Rd1 Kf8 2.Rh7 (2.Rh8+ Kg7) Kg8 *",
+			expected: "Rad1 Kf8 Rh7 ( Rh8+ Kg7 ) Kg8 *",
+			change:   "resolved ambiguous move 1. Rd1 as Rad1",
+		},
+		{
+			movetext: "1.Rd1 Kf8 *",
+			expected: "Rd1 Kf8 *",
+			change:   "left ambiguous move 1. Rd1 and the rest of its line unchanged",
+		},
+	}
+
+	for _, sample := range samples {
+		game := &types.Game{
+			Event:  "?",
+			Site:   "?",
+			Date:   UnknownDate,
+			Round:  "?",
+			White:  "?",
+			Black:  "?",
+			Result: "*",
+			FEN:    "4k3/8/8/8/8/8/4K3/R6R w - - 0 1",
+			Game:   sample.movetext,
+		}
+
+		changes := Game(game)
+
+		if game.Game != sample.expected {
+			t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", game.Game, sample.expected)
+		}
+
+		if len(changes) == 0 || changes[0] != sample.change {
+			t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", changes, sample.change)
+		}
+	}
+}
+
+func TestGameResult(t *testing.T) {
+	samples := []struct {
+		result   string
+		movetext string
+		expected string
+		marker   string
+		change   string
+	}{
+		{"*", "1.e4 e5 1-0", "1-0", "1-0", `set Result "*" from the termination marker 1-0`},
+		{"0-1", "1.e4 e5 *", "0-1", "0-1", `set termination marker * from the Result tag "0-1"`},
+		{"1-0", "1.e4 e5 0-1", "1-0", "0-1", `left Result "1-0" that contradicts the termination marker 0-1`},
+	}
+
+	for _, sample := range samples {
+		game := &types.Game{
+			Event:  "?",
+			Site:   "?",
+			Date:   UnknownDate,
+			Round:  "?",
+			White:  "?",
+			Black:  "?",
+			Result: sample.result,
+			Game:   sample.movetext,
+		}
+
+		changes := Game(game)
+
+		if game.Result != sample.expected || termination(game.Game) != sample.marker {
+			t.Errorf("Incorrect Result: \nresult: %v %v \nexpected: %v %v", game.Result, game.Game, sample.expected, sample.marker)
+		}
+
+		if !reflect.DeepEqual(changes, []string{sample.change}) {
+			t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", changes, sample.change)
+		}
+	}
+}
+
+func TestGameMoveNumbers(t *testing.T) {
+	game := &types.Game{
+		Event:  "?",
+		Site:   "?",
+		Date:   UnknownDate,
+		Round:  "?",
+		White:  "?",
+		Black:  "?",
+		Result: "*",
+		Game:   "1. {the king pawn} e4 e5 Nf3 *",
+	}
+
+	changes := Game(game)
+
+	expected := []string{"added 1 move numbers"}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", changes, expected)
+	}
+}
+
+func TestNormalizeResult(t *testing.T) {
+	samples := map[string]string{
+		"1-0":     "1-0",
+		" 0:1 ":   "0-1",
+		"½-½":     "1/2-1/2",
+		"1/2":     "1/2-1/2",
+		"0.5-0.5": "1/2-1/2",
+		"1–0":     "1-0",
+	}
+
+	for result, expected := range samples {
+		normalized, valid := NormalizeResult(result)
+		if !valid || normalized != expected {
+			t.Errorf("Incorrect Result for %q: \nresult: %v \nexpected: %v", result, normalized, expected)
+		}
+	}
+
+	if _, valid := NormalizeResult("?"); valid {
+		t.Errorf("Expected ? to be an unknown result")
+	}
+}
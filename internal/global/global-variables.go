@@ -9,5 +9,31 @@ var Logger *slog.Logger
 
 var VERSION = "1.0.3"
 var Output = ""
+var InputFormat = ""
+var Format = ""
+var Columns []string
+var Notation = ""
+var InputLang = ""
+var OutputLang = ""
+var Compress = false
 
 var AllowExperimental = false
+
+var Stdout = false
+var CountOnly = false
+var SplitBy = ""
+
+var Sort = ""
+var Limit = 0
+
+var Dedup = ""
+var DedupReport = ""
+var Reconcile = false
+
+var NormalizePlayers = false
+var PlayerAliases = ""
+var NormalizeEvents = false
+var EventAliases = ""
+
+var QueryFiles []string
+var Presets []string
@@ -10,21 +10,78 @@ Usage:
 The commands are:
 
 	bug 		start a bug report
+	classify	fill in missing opening tags from the moves
 	convert		convert a chessbase cbh to pgn
+	extract		extract EPD or FEN positions from games
+	fix		repair tags, results and moves of a pgn database
 	merge		reconcile multiple databases into one database
+	normalize	rewrite player, event and site names consistently
+	player		report the results of a player for preparation
 	query		query a pgn database
+	stats		summarize the games of pgn databases
+	tree		explore the opening tree of pgn databases
+	validate	check pgn databases for illegal moves and bad tags
 	version		print pgn-tools version
 
 Global flags available:
 
 	experimental enables experimental features
+	columns		selects the columns of csv and tsv output
+	compress	writes outputs gzip compressed
+	format		writes games as pgn, json, ndjson, csv or tsv
+	input-format	reads inputs as pgn or chessbase without sniffing
+	input-lang	reads moves with the piece letters of a language
+	notation	writes movetext in san, uci, lan or figurine notation
 	output		defines the output path in commands that use an output
+	output-lang	writes moves with the piece letters of a language
 	verbose		print debug messages
 
+Query, merge and convert write pgn unless "--format" is given or the output
+path ends in .json, .ndjson, .csv or .tsv. Json games hold their tags, the main
+line moves in SAN and UCI, the comments with the ply they follow, the clock
+comment of every move and the movetext in pgn export format.
+
+Csv and tsv write a header row and one row per game. The columns are query
+keys given to "--columns", for example "--columns white,black,whiteelo,plies",
+and default to the seven tag roster with the ratings and eco.
+
+Movetext is written in standard algebraic notation as read. With "--notation"
+the moves are replayed and rewritten in uci (e2e4, e7e8q), lan (Ng1-f3, e4xd5)
+or figurine (♘f3) notation, including the moves of variations.
+
+Localized piece letters are read with "--input-lang" and written with
+"--output-lang", for example "--input-lang de" reads Sf3 as Nf3. The languages
+are cs, da, de, en, es, fr, it, nl, no, pl, pt and sv.
+
+Inputs are recognized by their content rather than their extension: a pgn
+database holds a tag pair such as [Event "..."] and a chessbase database starts
+with the header signature. "--input-format pgn" reads files that have no tag
+pairs, such as bare movetext.
+
+Every command reads the pgn database from stdin when the input path is "-",
+outputs derived from stdin are named after stdin in the working directory.
+Convert needs the chessbase files and can not read stdin.
+
+Inputs compressed with gzip or bzip2 are read as is, the compression is found
+from the content rather than the extension. Zstandard and xz inputs have to be
+decompressed first. Outputs ending in .gz are written gzip compressed, and
+"--compress" compresses every output. Outputs derived from a gzip compressed
+input stay compressed.
+
 Use "pgn-tools help <command>" for more information about a command.`
 	Bug = `Usage: pgn-tools bug
 
 Bug opens the default browser and starts a new bug report.`
+	Classify = `Usage: pgn-tools classify PATH [--flags]
+
+Classify replays the opening of every game in the pgn database and fills in
+missing ECO, Opening and Variation tags from the embedded opening table.
+
+Games starting from a custom position are left unchanged.
+
+Flags available:
+	output		writes to output path
+	overwrite	replaces existing opening tags`
 	Convert = `Usage: pgn-tools convert INPUT_PATH OUTPUT_PATH [--flags]
 
 Convert is an experimental feature and comes with some drawbacks.
@@ -33,17 +90,183 @@ Convert takes a chessbase database which must include a header file (.cbh),
 player file (.cbp), tournament file (.cbt), and game file (.cbg), all in the
 same directory as the input path, and converts it to a pgn database.
 
+The output can also be written as json, ndjson, csv or tsv with "--format" or
+an output path ending in the extension of the format.
+
 Be aware that convert currently skips games that include variations, doesn't
-mark check and checkmates, and doesn't provide disambiguations.`
+mark check and checkmates, and doesn't provide disambiguations.
+
+Flags available:
+	format		writes the games as pgn, json, ndjson, csv or tsv`
+	Extract = `Usage: pgn-tools extract PATH [--flags]
+
+Extract replays the main line of every game in the pgn database and writes
+positions as EPD lines for building training and test suites:
+
+	r1bqkbnr/pppp1ppp/2n5/1B2p3/4P3/5N2/PPPP1PPP/RNBQK2R b KQkq -
+	id "sample.pgn:1:5"; bm Nf6; c0 "Carlsen, Magnus - Bacrot, Etienne, ...";
+
+By default the final position of every game is written, or instead:
+
+	--ply N		the position after N plies, 0 is the starting position
+	--after-move N	every position once move N has been played
+	--every-ply	every position of the game
+
+The bm opcode holds the move played in the position, id names the database,
+game number and ply, and c0 holds the players, event, date and result. Pick
+the opcodes with "--opcodes id,bm,c0". With "--fen" plain FEN lines are
+written instead.
+
+Positions are written next to the database as NAME_positions.epd unless one
+of the output flags is used.
+
+Flags available:
+	after-move	writes every position after the move number
+	every-ply	writes every position
+	fen		writes FEN lines instead of EPD
+	opcodes		selects the EPD opcodes
+	output		writes to output path
+	ply		writes the position after the number of plies
+	stdout		streams positions to stdout`
+	Fix = `Usage: pgn-tools fix PATH [--flags]
+
+Fix repairs the common defects of a pgn database and writes the repaired games
+next to the input. Every change is listed per game in a change log named after
+the output with a .log extension.
+
+The repairs are:
+
+	missing seven tag roster tags are added as "?", with "????.??.??" for the
+	date and the termination marker for the result
+	results such as 1:0, ½-½ or 1/2 are normalized in tags and movetext
+	a Result tag of * is set from the termination marker and a * marker from
+	the Result tag, two different results are reported
+	moves are rewritten in standard algebraic notation, adding check marks,
+	writing 0-0 as O-O and fixing over and under disambiguation
+	move numbers are added
+	control characters are stripped from tags and comments
+
+An under disambiguated move is only resolved when a single candidate allows
+the next move. A move that can not be replayed is kept with the rest of its
+line and reported in the change log. Every other tag is kept as it was read.
+
+Flags available:
+	change-log	writes the change log to path
+	input-lang	reads moves with the piece letters of a language
+	output		writes to output path`
 	Merge = `Usage: pgn-tools merge PATH... '-o | --output PATH'  [--flags]
 
-Merge takes multiple pgn database paths or directories containing pgn databases
-and merges them in the output.`
-	Query = `Usage: pgn-tools query PATH "key=value" [--flags]
+Merge takes multiple pgn database paths, zip archives or directories containing
+pgn databases and merges them in the output. Zip archives found while walking a
+directory are merged as well. The output is written to a temporary file that
+replaces the output path once the merge succeeds.
+
+Duplicates are removed with "--dedup KEY" where the key is one of:
+
+	players		same players, date, result and moves
+	moves		identical moves with matching surnames and year
+
+Of every set of duplicates the most complete game is kept, the dropped games
+are logged in debug mode or written to the path given to "--dedup-report".
+
+With "--reconcile" the duplicates are merged field by field instead:
+
+	- known values are preferred over empty or "?" values
+	- the more precise date is preferred, 2023.01.14 over 2023.??.??
+	- annotated movetext is preferred over bare movetext
+	- otherwise the value from the path given first on the command line wins
+
+Reconcile uses the moves key unless "--dedup" is given, so copies with dates
+of different precision are found.
+
+Player names are rewritten to "Last, First" with "--normalize-players", titles
+such as GM are dropped and diacritics folded so "GM Carlsen, M." becomes
+"Carlsen, M". A name such as "Ding Liren" is only reordered when the aliases
+know it. Other spellings are resolved with an alias file given to
+"--player-aliases PATH" or players.aliases in the pgn-tools config directory:
+
+	# canonical name = aliases separated by semicolons
+	Carlsen, Magnus = Carlsen, M; Magnus Carlsen
+	Nepomniachtchi, Ian = Nepo
+
+Abbreviated names such as "Carlsen, M" are expanded when they match exactly
+one name in the alias file.
+
+Event and Site tags are resolved the same way with "--normalize-events" and
+an alias file given to "--event-aliases PATH" or events.aliases in the
+pgn-tools config directory. Normalizing runs before duplicates are removed.
+
+Games are written in the order the inputs are read unless sorted with
+"--sort KEYS", using the keys described in "pgn-tools help query":
+
+	--sort date		oldest games first
+	--sort event,round	grouped by event in round order
+	--sort player		grouped by the white then black player
+
+Sorting spills sorted runs to temporary files and merges them, so databases
+larger than memory can be sorted.
 
-Query takes a pgn database path and the query(ies) which is a string array of
+Flags available:
+	dedup			removes duplicate games by the key
+	dedup-report		writes a report of the dropped duplicates
+	event-aliases		resolves event and site names with an alias file
+	format			writes the merged games as pgn, json, ndjson, csv or tsv
+	normalize-events	rewrites event and site names to a consistent spelling
+	normalize-players	rewrites player names to a consistent spelling
+	output			writes to output path
+	player-aliases		resolves player names with an alias file
+	reconcile		merges duplicates field by field
+	sort			sorts the merged games by the keys`
+	Normalize = `Usage: pgn-tools normalize PATH [--flags]
+
+Normalize rewrites the player, event and site names of every game in the pgn
+database to a consistent spelling.
+
+Player names are written as "Last, First" without titles or diacritics and
+resolved with the player aliases, names without a comma are only reordered
+when the order is clear or the aliases know it. Event and Site tags are
+resolved with the event aliases, one alias file holds both:
+
+	# canonical name = aliases separated by semicolons
+	Tata Steel Masters = Tata Steel-A; Corus Group A
+	Wijk aan Zee NED = Wijk aan Zee
+
+An alias also matches the name with a year in it, so "Tata Steel-A 2023"
+becomes "Tata Steel Masters". The alias files default to players.aliases and
+events.aliases in the pgn-tools config directory.
+
+By default every name is normalized, use "--normalize-players" or
+"--normalize-events" to only rewrite those.
+
+Flags available:
+	event-aliases		resolves event and site names with an alias file
+	normalize-events	only rewrites event and site names
+	normalize-players	only rewrites player names
+	output			writes to output path
+	player-aliases		resolves player names with an alias file`
+	Query = `Usage: pgn-tools query PATH... "key=value" [--flags]
+
+Query takes pgn database paths and the query(ies) which is a string array of
 key value pairs used to match the game.
 
+Paths can be files, directories containing pgn databases, zip archives or glob
+patterns such as "twic/*.pgn". A single member of an archive is named as
+"games.zip!2023/wijk.pgn". The databases are queried concurrently and the matches are
+written to a single combined result named after the first database.
+
+Queries can also be loaded from a query file with "--query-file PATH" or from
+the presets in the user config (presets.q in the pgn-tools config directory)
+with "--preset NAME". Both files hold one named query per line:
+
+	# comments start with a hash
+	elite-classical: elo>=2600, event!=blitz
+	najdorf: eco>=B90,
+		eco<=B99
+
+Lines starting with whitespace continue the previous query. Every named query
+writes its own output, and an inline query given alongside them is added to
+each of them.
+
 There are two types of queries, string queries and integer queries.
 
 String queries look for a partial string in the game metadata based on the key,
@@ -51,15 +274,139 @@ for example if you wanted to look for Fide events you would use "event=fide".
 
 Integer queries on the other hand compare the value from the query to the key
 to match results. For example, if you were looking for games above 2500 elo you
-could use "elo>=2500".
+could use "elo>=2500". The plies key counts the half moves of the main line so
+"plies<=50" finds short games.
+
+ECO codes can be compared as a range, the code is compared on the length of
+the value so "eco>=B90,eco<=B99" and "eco=B9" both find the Najdorf. The eco,
+opening and variation keys fall back to the embedded opening classifier for
+games without those tags.
+
+Matches are written next to the database unless one of the output flags below
+is used. Split writes one file per distinct value of the key, for example
+"--split-by player" writes every game to a file for each of its players and
+"--split-by year" writes a file per year. Values that would share a file name
+are numbered, as in games_Carlsen_Magnus_2.pgn.
+
+Matches are written in file order unless sorted with "--sort KEYS", a comma
+separated list of keys each optionally followed by asc or desc. Combined with
+"--limit N" only the first N games in sort order are kept in memory, so
+"--sort 'date desc,avgelo desc' --limit 50" finds the 50 most recent games with
+the highest average rating.
+
+With "--normalize-players" or "--player-aliases PATH" player names are
+normalized as described in "pgn-tools help merge" before matching, and the
+player, white and black values of the query are normalized the same way so
+"player=GM Carlsen,Magnus" matches "Carlsen, Magnus". With "--normalize-events"
+or "--event-aliases PATH" the event and site values are resolved the same way
+as the Event and Site tags.
 
 Flags available:
-	output		writes to output path
+	columns			selects the columns of csv and tsv output
+	count			prints the number of matches instead of writing them
+	event-aliases		resolves event and site names with an alias file
+	format			writes matches as pgn, json, ndjson, csv or tsv
+	limit			writes at most N matches
+	normalize-events	rewrites event and site names to a consistent spelling
+	normalize-players	rewrites player names to a consistent spelling
+	output			writes to output path
+	player-aliases		resolves player names with an alias file
+	preset			runs a named query from the user presets
+	query-file		runs the named queries in a query file
+	sort			sorts the matches by the keys
+	split-by		writes matches into one file per value of the key
+	stdout			streams matches to stdout for use in pipelines
 
 Example queries:
 "elo>=2300"
 "player=Carlsen"
-"site!=chess.com"`
+"site!=chess.com"
+"eco>=B90,eco<=B99"
+"opening=sicilian"`
+	Player = `Usage: pgn-tools player NAME PATH... [--flags]
+
+Player reports the games of a player: the score overall and by colour, by
+opening, by year and by time control, the performance rating against rated
+opponents and the best wins and worst losses by the rating of the opponent.
+
+The name matches a player like "player=NAME" in query, a name equal to the
+White or Black tag is preferred over a name contained in it. Games without a
+result are not counted.
+
+Openings are grouped by the ECO code and the first moves. Time controls are
+grouped from the TimeControl tag as bullet, blitz, rapid, classical or
+correspondence, estimating a game as the base time and 40 moves of increment.
+
+Paths can be files, directories, zip archives or glob patterns like in query.
+The report is written to stdout as markdown or as json with "--format json".
+
+Flags available:
+	format		writes the report as markdown or json
+	player-aliases	resolves the player name with an alias file
+	top		lists the number of openings, wins and losses, default 10`
+	Stats = `Usage: pgn-tools stats PATH... ["key=value"] [--flags]
+
+Stats summarizes the games of pgn databases: the game count, the date range,
+the result distribution, the average ratings and length, the share of decisive
+games by colour and the most frequent players, events and openings.
+
+Paths can be files, directories, zip archives or glob patterns like in query.
+The games can be filtered by a query, a query file or a preset, every game is
+counted when none is given.
+
+The summary is written to stdout as text or as json with "--format json".
+
+Flags available:
+	format		writes the summary as text or json
+	preset		filters the games by a preset query
+	query-file	filters the games by the query of a file
+	top		lists the number of players, events and openings, default 10`
+	Tree = `Usage: pgn-tools tree PATH... ["key=value"] [--flags]
+
+Tree builds the opening tree of pgn databases below a position. Every move
+played in the position is listed with the number of games, the score of the
+side that played it, the results, the average rating of the side that played
+it and the date it was last played.
+
+The position is the starting position, a FEN given with --fen or the position
+after the moves given with --moves, which are played from the FEN when both
+are given. Games are found by position so transpositions are included, games
+without a result are not counted.
+
+Paths can be files, directories, zip archives or glob patterns like in query.
+The games can be filtered by a query, a query file or a preset, for example
+"white=Carlsen" for the repertoire of a player with white.
+
+The tree is written to stdout as text or as json with "--format json".
+
+Example:
+	pgn-tools tree games.pgn --moves "1.e4 c5 2.Nf3" --depth 3
+
+Flags available:
+	depth		builds the tree the number of half moves deep, default 1
+	fen		starts the tree from a position
+	format		writes the tree as text or json
+	moves		starts the tree after the moves
+	preset		filters the games by a preset query
+	query-file	filters the games by the query of a file`
+	Validate = `Usage: pgn-tools validate PATH... [--flags]
+
+Validate checks every game of the pgn databases and prints each problem as
+"file:line: problem". Paths can be files, directories, zip archives or glob
+patterns like in query.
+
+The problems reported are:
+
+	moves that are illegal, ambiguous or not valid notation, in variations too
+	a termination marker that is missing or does not match the Result tag
+	dates that are not YYYY.MM.DD with question marks for unknown parts
+	tag pairs that are malformed or repeated, bad results and ratings
+	variations that are not closed
+
+Validate exits with status 1 when a problem is found.
+
+Flags available:
+	input-lang	reads moves with the piece letters of a language`
 	Version = `Usage: pgn-tools version
 
 Version prints the binaries version details.`
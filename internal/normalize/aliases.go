@@ -0,0 +1,165 @@
+package normalize
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/gavink97/pgn-tools/internal/global"
+)
+
+// Aliases maps the known spellings of a name onto its canonical spelling.
+// Spellings are compared on their Key so case, punctuation and diacritics do
+// not matter.
+type Aliases struct {
+	canonical map[string]string
+	names     []string
+}
+
+func NewAliases() *Aliases {
+	return &Aliases{canonical: map[string]string{}}
+}
+
+// Add registers the aliases of a canonical name, the canonical name is an
+// alias of itself.
+func (a *Aliases) Add(canonical string, aliases ...string) {
+	if _, exists := a.canonical[Key(canonical)]; !exists {
+		a.names = append(a.names, canonical)
+	}
+
+	for _, alias := range append([]string{canonical}, aliases...) {
+		a.canonical[Key(alias)] = canonical
+	}
+}
+
+func (a *Aliases) Lookup(name string) (string, bool) {
+	canonical, exists := a.canonical[Key(name)]
+	return canonical, exists
+}
+
+// Names returns the canonical names in the order they were added.
+func (a *Aliases) Names() []string {
+	return a.names
+}
+
+// LoadAliases reads an alias file where every line is written as
+// "canonical = alias; alias". Lines starting with whitespace add aliases to
+// the previous line and lines starting with # are comments.
+func LoadAliases(fileName string) (*Aliases, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		err := file.Close()
+		if err != nil {
+			global.Logger.Warn(fmt.Sprintf("An error occured closing %s", fileName))
+		}
+	}()
+
+	aliases := NewAliases()
+	canonical := ""
+
+	scanner := bufio.NewScanner(file)
+	lineNo := 0
+
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if line[0] == ' ' || line[0] == '\t' {
+			if canonical == "" {
+				return nil, fmt.Errorf("%s:%d: continuation without a name", fileName, lineNo)
+			}
+
+			aliases.Add(canonical, splitAliases(trimmed)...)
+			continue
+		}
+
+		name, rest, _ := strings.Cut(trimmed, "=")
+		name = strings.TrimSpace(name)
+
+		if name == "" {
+			return nil, fmt.Errorf("%s:%d: expected 'name = alias; alias', got: %s", fileName, lineNo, trimmed)
+		}
+
+		canonical = name
+		aliases.Add(canonical, splitAliases(rest)...)
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return aliases, nil
+}
+
+func splitAliases(list string) []string {
+	var aliases []string
+
+	for alias := range strings.SplitSeq(list, ";") {
+		alias = strings.TrimSpace(alias)
+		if alias != "" {
+			aliases = append(aliases, alias)
+		}
+	}
+
+	return aliases
+}
+
+// AliasFile is the user config alias file of the kind, it is loaded when no
+// alias file is given on the command line.
+func AliasFile(kind string) (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, "pgn-tools", kind+".aliases"), nil
+}
+
+// Key reduces a name to the letters and digits that identify it, folded to
+// lower case ascii.
+func Key(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, Fold(name))
+}
+
+var diacritics = strings.NewReplacer(
+	"à", "a", "á", "a", "â", "a", "ã", "a", "ä", "a", "å", "a", "ā", "a", "ă", "a", "ą", "a",
+	"À", "A", "Á", "A", "Â", "A", "Ã", "A", "Ä", "A", "Å", "A", "Ā", "A", "Ă", "A", "Ą", "A",
+	"æ", "ae", "Æ", "AE", "ç", "c", "ć", "c", "č", "c", "Ç", "C", "Ć", "C", "Č", "C",
+	"ď", "d", "đ", "d", "ð", "d", "Ď", "D", "Đ", "D", "Ð", "D",
+	"è", "e", "é", "e", "ê", "e", "ë", "e", "ē", "e", "ė", "e", "ę", "e", "ě", "e",
+	"È", "E", "É", "E", "Ê", "E", "Ë", "E", "Ē", "E", "Ė", "E", "Ę", "E", "Ě", "E",
+	"ğ", "g", "Ğ", "G", "ì", "i", "í", "i", "î", "i", "ï", "i", "ī", "i", "ı", "i",
+	"Ì", "I", "Í", "I", "Î", "I", "Ï", "I", "Ī", "I", "İ", "I",
+	"ł", "l", "ľ", "l", "Ł", "L", "Ľ", "L", "ñ", "n", "ń", "n", "ň", "n", "Ñ", "N", "Ń", "N", "Ň", "N",
+	"ò", "o", "ó", "o", "ô", "o", "õ", "o", "ö", "o", "ø", "o", "ő", "o", "ō", "o",
+	"Ò", "O", "Ó", "O", "Ô", "O", "Õ", "O", "Ö", "O", "Ø", "O", "Ő", "O", "Ō", "O",
+	"œ", "oe", "Œ", "OE", "ř", "r", "Ř", "R", "ś", "s", "š", "s", "ş", "s", "ș", "s", "ß", "ss",
+	"Ś", "S", "Š", "S", "Ş", "S", "Ș", "S", "ť", "t", "ţ", "t", "ț", "t", "Ť", "T", "Ţ", "T", "Ț", "T",
+	"ù", "u", "ú", "u", "û", "u", "ü", "u", "ū", "u", "ů", "u", "ű", "u",
+	"Ù", "U", "Ú", "U", "Û", "U", "Ü", "U", "Ū", "U", "Ů", "U", "Ű", "U",
+	"ý", "y", "ÿ", "y", "Ý", "Y", "ź", "z", "ż", "z", "ž", "z", "Ź", "Z", "Ż", "Z", "Ž", "Z",
+	"þ", "th", "Þ", "Th",
+)
+
+// Fold replaces latin letters with diacritics by their plain ascii letters.
+func Fold(name string) string {
+	return diacritics.Replace(name)
+}
@@ -0,0 +1,261 @@
+package normalize
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/gavink97/pgn-tools/internal/types"
+)
+
+// Normalizer rewrites the names of a game to a consistent spelling. The
+// built-in heuristics always apply, the alias dictionaries are optional. One
+// event dictionary resolves both the Event and Site tags.
+type Normalizer struct {
+	NormalizePlayers bool
+	NormalizeEvents  bool
+	Players          *Aliases
+	Events           *Aliases
+}
+
+// Game normalizes the names of the game in place and reports whether any of
+// them changed.
+func (n *Normalizer) Game(game *types.Game) bool {
+	before := [4]string{game.White, game.Black, game.Event, game.Site}
+
+	if n.NormalizePlayers {
+		game.White = n.Player(game.White)
+		game.Black = n.Player(game.Black)
+	}
+
+	if n.NormalizeEvents {
+		game.Event = n.Event(game.Event)
+		game.Site = n.Event(game.Site)
+	}
+
+	return before != [4]string{game.White, game.Black, game.Event, game.Site}
+}
+
+// Player normalizes a player name with the heuristics of Player and resolves
+// it against the player aliases. A name Player leaves in its written order is
+// reordered when exactly one of its orders is a known name, and an
+// abbreviated name such as "Carlsen, M" is expanded when exactly one
+// canonical name matches it.
+func (n *Normalizer) Player(name string) string {
+	normalized := Player(name)
+
+	if n.Players == nil {
+		return normalized
+	}
+
+	for _, spelling := range []string{name, normalized} {
+		if canonical, exists := n.Players.Lookup(spelling); exists {
+			return canonical
+		}
+	}
+
+	if !strings.Contains(normalized, ",") {
+		match := ""
+
+		for _, order := range orders(normalized) {
+			canonical, exists := n.Players.Lookup(order)
+			if !exists || canonical == match {
+				continue
+			}
+
+			if match != "" {
+				return normalized
+			}
+			match = canonical
+		}
+
+		if match != "" {
+			return match
+		}
+	}
+
+	return expandInitials(normalized, n.Players.Names())
+}
+
+// orders returns every "Last, First" reading of a name written without a
+// comma.
+func orders(name string) []string {
+	fields := strings.Fields(name)
+
+	var orders []string
+	for split := 1; split < len(fields); split++ {
+		orders = append(orders, join(fields[split:], fields[:split]), join(fields[:split], fields[split:]))
+	}
+
+	return orders
+}
+
+var titles = []string{"GM", "IM", "FM", "CM", "WGM", "WIM", "WFM", "WCM", "NM", "Dr", "Dr.", "Prof."}
+
+var particles = []string{"van", "von", "der", "den", "de", "da", "del", "di", "du", "la", "le", "ter", "ten"}
+
+// Player rewrites a player name to "Last, First" when its order is clear:
+// titles are dropped, diacritics folded, "Last F.", "F. Last" and names with
+// a particle such as "Jorden van Foreest" reordered and initials written
+// without dots. "Magnus Carlsen" and "Ding Liren" may be written either way
+// round so they keep their order, only the aliases reorder them. Unknown
+// names are returned unchanged.
+func Player(name string) string {
+	name = strings.TrimSpace(name)
+	if name == "" || name == "?" {
+		return name
+	}
+
+	name = Fold(name)
+
+	last, first, found := strings.Cut(name, ",")
+	if found {
+		return join(words(last), words(first))
+	}
+
+	fields := words(name)
+
+	switch {
+	case len(fields) < 2:
+		return strings.Join(fields, " ")
+
+	// Carlsen M or Carlsen M.
+	case isInitial(fields[len(fields)-1]):
+		split := len(fields) - 1
+		for split > 1 && isInitial(fields[split-1]) {
+			split--
+		}
+		return join(fields[:split], fields[split:])
+
+	// M. Carlsen
+	case isInitial(fields[0]):
+		split := 1
+		for split < len(fields)-1 && isInitial(fields[split]) {
+			split++
+		}
+		return join(fields[split:], fields[:split])
+	}
+
+	// Jorden van Foreest
+	for split := 1; split < len(fields); split++ {
+		if slices.Contains(particles, fields[split]) {
+			return join(fields[split:], fields[:split])
+		}
+	}
+
+	return strings.Join(fields, " ")
+}
+
+func words(name string) []string {
+	var fields []string
+
+	for _, field := range strings.Fields(name) {
+		if slices.Contains(titles, field) {
+			continue
+		}
+
+		if isInitial(field) {
+			field = strings.TrimSuffix(field, ".")
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+func join(last []string, first []string) string {
+	if len(first) == 0 {
+		return strings.Join(last, " ")
+	}
+
+	return strings.Join(last, " ") + ", " + strings.Join(first, " ")
+}
+
+func isInitial(word string) bool {
+	word = strings.TrimSuffix(word, ".")
+	return len(word) == 1 && word[0] >= 'A' && word[0] <= 'Z'
+}
+
+// expandInitials resolves "Carlsen, M" to the only known name with the same
+// surname and initials, the name is returned unchanged otherwise.
+func expandInitials(name string, known []string) string {
+	last, first, found := strings.Cut(name, ", ")
+	if !found {
+		return name
+	}
+
+	initials := strings.Fields(first)
+	if len(initials) == 0 || !slices.ContainsFunc(initials, isInitial) {
+		return name
+	}
+
+	match := ""
+
+	for _, candidate := range known {
+		candidateLast, candidateFirst, _ := strings.Cut(Player(candidate), ", ")
+		if Key(candidateLast) != Key(last) {
+			continue
+		}
+
+		names := strings.Fields(candidateFirst)
+		if len(names) < len(initials) {
+			continue
+		}
+
+		matches := true
+		for i, initial := range initials {
+			if isInitial(initial) && !strings.HasPrefix(names[i], initial) {
+				matches = false
+			}
+			if !isInitial(initial) && Key(initial) != Key(names[i]) {
+				matches = false
+			}
+		}
+
+		if !matches {
+			continue
+		}
+
+		if match != "" && match != candidate {
+			return name
+		}
+		match = candidate
+	}
+
+	if match == "" {
+		return name
+	}
+
+	return match
+}
+
+var yearPattern = regexp.MustCompile(`^(.*?)[\s,-]*\b(?:19|20)\d\d\b[\s,-]*(.*)$`)
+
+// Event resolves an event or site name against the event aliases. Names are
+// matched as written first and then without the year so that an alias for
+// "Tata Steel-A" also covers "Tata Steel-A 2023". Unknown names only have
+// their whitespace collapsed.
+func (n *Normalizer) Event(name string) string {
+	name = strings.Join(strings.Fields(name), " ")
+
+	if n.Events == nil || name == "" || name == "?" {
+		return name
+	}
+
+	if canonical, exists := n.Events.Lookup(name); exists {
+		return canonical
+	}
+
+	match := yearPattern.FindStringSubmatch(name)
+	if match == nil {
+		return name
+	}
+
+	withoutYear := strings.TrimSpace(match[1] + " " + match[2])
+	if canonical, exists := n.Events.Lookup(withoutYear); exists {
+		return canonical
+	}
+
+	return name
+}
@@ -0,0 +1,118 @@
+package normalize
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gavink97/pgn-tools/internal/types"
+)
+
+func TestPlayer(t *testing.T) {
+	tests := map[string]string{
+		"Carlsen, Magnus":      "Carlsen, Magnus",
+		"Magnus Carlsen":       "Magnus Carlsen",
+		"GM Magnus Carlsen":    "Magnus Carlsen",
+		"Carlsen Magnus":       "Carlsen Magnus",
+		"Ding Liren":           "Ding Liren",
+		"M. Carlsen":           "Carlsen, M",
+		"Carlsen,Magnus":       "Carlsen, Magnus",
+		"Carlsen, M.":          "Carlsen, M",
+		"Carlsen M.":           "Carlsen, M",
+		"Jorden van Foreest":   "van Foreest, Jorden",
+		"Ljubojević, Ljubomir": "Ljubojevic, Ljubomir",
+		"Carlsen":              "Carlsen",
+		"?":                    "?",
+	}
+
+	for name, expected := range tests {
+		result := Player(name)
+		if result != expected {
+			t.Errorf("Incorrect Result for %s: \nresult: %v \nexpected: %v", name, result, expected)
+		}
+	}
+}
+
+func TestNormalizerAliases(t *testing.T) {
+	sample := `# players
+Carlsen, Magnus = Carlsen, M; Magnus Carlsen
+Nepomniachtchi, Ian = Nepo
+	Nepomniachtchi, Ian A
+Giri, Anish
+Ding, Liren
+`
+
+	file := filepath.Join(t.TempDir(), "players.aliases")
+
+	err := os.WriteFile(file, []byte(sample), 0600)
+	if err != nil {
+		t.Fatalf("An error occured writing alias file: %v", err)
+	}
+
+	aliases, err := LoadAliases(file)
+	if err != nil {
+		t.Fatalf("An error occured loading aliases: %v", err)
+	}
+
+	normalizer := &Normalizer{Players: aliases}
+
+	tests := map[string]string{
+		"carlsen, m":             "Carlsen, Magnus",
+		"Nepo":                   "Nepomniachtchi, Ian",
+		"Nepomniachtchi, Ian A.": "Nepomniachtchi, Ian",
+		"Giri, A.":               "Giri, Anish",
+		"Anish Giri":             "Giri, Anish",
+		"Ding Liren":             "Ding, Liren",
+		"Carlsen Magnus":         "Carlsen, Magnus",
+		"GM Magnus Carlsen":      "Carlsen, Magnus",
+		"Hikaru Nakamura":        "Hikaru Nakamura",
+		"Caruana, F":             "Caruana, F",
+	}
+
+	for name, expected := range tests {
+		result := normalizer.Player(name)
+		if result != expected {
+			t.Errorf("Incorrect Result for %s: \nresult: %v \nexpected: %v", name, result, expected)
+		}
+	}
+}
+
+func TestNormalizerEvents(t *testing.T) {
+	aliases := NewAliases()
+	aliases.Add("Tata Steel Masters", "Tata Steel-A", "Corus Group A")
+	aliases.Add("Wijk aan Zee NED", "Wijk aan Zee")
+
+	normalizer := &Normalizer{NormalizeEvents: true, Events: aliases}
+
+	game := &types.Game{
+		Event: "Tata  Steel-A 2023",
+		Site:  "Wijk aan Zee",
+		White: "Magnus Carlsen",
+	}
+
+	if !normalizer.Game(game) {
+		t.Fatalf("Incorrect Result: \nresult: %v \nexpected: %v", false, true)
+	}
+
+	expected := types.Game{
+		Event: "Tata Steel Masters",
+		Site:  "Wijk aan Zee NED",
+		White: "Magnus Carlsen",
+	}
+
+	if !reflect.DeepEqual(*game, expected) {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", *game, expected)
+	}
+
+	for name, expected := range map[string]string{
+		"2005 Corus Group A":          "Tata Steel Masters",
+		"Tata Steel Challengers 2023": "Tata Steel Challengers 2023",
+		"?":                           "?",
+	} {
+		result := normalizer.Event(name)
+		if result != expected {
+			t.Errorf("Incorrect Result for %s: \nresult: %v \nexpected: %v", name, result, expected)
+		}
+	}
+}
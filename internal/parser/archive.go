@@ -0,0 +1,151 @@
+package parser
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gavink97/pgn-tools/internal/global"
+)
+
+// Stdin is the input name that reads a database from standard input.
+const Stdin = "-"
+
+// archiveSeparator joins the path of a zip archive and the name of a member,
+// as in "games.zip!2023/wijk.pgn".
+const archiveSeparator = "!"
+
+var zipMagic = []byte("PK\x03\x04")
+
+// IsArchive reports whether the file is a zip archive from its magic bytes.
+func IsArchive(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+
+	header := make([]byte, len(zipMagic))
+	_, err = io.ReadFull(file, header)
+	err = errors.Join(err, file.Close())
+
+	return err == nil && bytes.Equal(header, zipMagic)
+}
+
+// ArchiveMember names a member of a zip archive as an input.
+func ArchiveMember(archive string, name string) string {
+	return archive + archiveSeparator + name
+}
+
+// SplitArchivePath splits an input naming a member of a zip archive into the
+// archive and the member name. Existing files are never split.
+func SplitArchivePath(path string) (string, string, bool) {
+	archive, member, found := strings.Cut(path, archiveSeparator)
+	if !found || archive == "" || member == "" {
+		return path, "", false
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		return path, "", false
+	}
+
+	return archive, member, true
+}
+
+// ArchiveInputs lists the pgn databases inside a zip archive, compressed
+// databases included. Members are found by their content unless the input
+// format is given.
+func ArchiveInputs(archive string) ([]string, error) {
+	r, err := zip.OpenReader(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var inputs []string
+
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		format := global.InputFormat
+		if format == "" {
+			file, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+
+			reader, err := decompress(ArchiveMember(archive, f.Name), file)
+			if err != nil {
+				global.Logger.Warn(err.Error())
+				continue
+			}
+
+			format, err = sniff(reader)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if format != PGNInput {
+			continue
+		}
+
+		inputs = append(inputs, ArchiveMember(archive, f.Name))
+	}
+
+	return inputs, nil
+}
+
+type archiveMember struct {
+	io.ReadCloser
+	archive *zip.ReadCloser
+}
+
+func (m *archiveMember) Close() error {
+	return errors.Join(m.ReadCloser.Close(), m.archive.Close())
+}
+
+// openSource opens the raw content of an input.
+func openSource(fileName string) (io.ReadCloser, error) {
+	if fileName == Stdin {
+		return io.NopCloser(os.Stdin), nil
+	}
+
+	archive, member, found := SplitArchivePath(fileName)
+	if !found {
+		return os.Open(fileName)
+	}
+
+	r, err := zip.OpenReader(archive)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := r.Open(member)
+	if err != nil {
+		return nil, errors.Join(err, r.Close())
+	}
+
+	return &archiveMember{ReadCloser: f, archive: r}, nil
+}
+
+// localPath returns the path outputs of an input are named after, stdin is
+// named after the working directory and archive members after the archive
+// directory.
+func localPath(input string) string {
+	if input == Stdin {
+		return "stdin.pgn"
+	}
+
+	archive, member, found := SplitArchivePath(input)
+	if !found {
+		return input
+	}
+
+	return filepath.Join(filepath.Dir(archive), filepath.Base(member))
+}
@@ -0,0 +1,100 @@
+package parser
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestArchiveInputs(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "games.zip")
+	sample := "[Event \"Test\"]\n[White \"A\"]\n[Black \"B\"]\n[Result \"*\"]\n\n1.e4 *\n"
+
+	f, err := os.Create(archive)
+	if err != nil {
+		t.Fatalf("An error occured creating file: %v", err)
+	}
+
+	// members are found by their content rather than their name
+	members := map[string]string{
+		"2023/wijk.pgn": sample,
+		"readme.pgn":    "Games of the 2023 season",
+	}
+
+	z := zip.NewWriter(f)
+	for name, content := range members {
+		w, err := z.Create(name)
+		if err != nil {
+			t.Fatalf("An error occured creating member: %v", err)
+		}
+		_, _ = w.Write([]byte(content))
+	}
+
+	err = z.Close()
+	if err == nil {
+		err = f.Close()
+	}
+	if err != nil {
+		t.Fatalf("An error occured writing zip: %v", err)
+	}
+
+	if !IsArchive(archive) {
+		t.Errorf("Expected %s to be an archive", archive)
+	}
+
+	inputs, err := ArchiveInputs(archive)
+	if err != nil {
+		t.Fatalf("An error occured listing archive: %v", err)
+	}
+
+	expected := []string{archive + "!2023/wijk.pgn"}
+	if !reflect.DeepEqual(inputs, expected) {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", inputs, expected)
+	}
+
+	if !VerifyPGNInput(inputs[0]) {
+		t.Errorf("Expected an archive member to be a valid input")
+	}
+
+	games, err := ParsePGN(inputs[0])
+	if err != nil {
+		t.Fatalf("An error occured parsing archive member: %v", err)
+	}
+
+	if len(games) != 1 || games[0].White != "A" {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", games, sample)
+	}
+
+	output := OutputPath(inputs[0], "classified")
+	if output != filepath.Join(dir, "wijk_classified.pgn") {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", output, filepath.Join(dir, "wijk_classified.pgn"))
+	}
+}
+
+func TestParseStdin(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("An error occured creating pipe: %v", err)
+	}
+
+	stdin := os.Stdin
+	os.Stdin = r
+	defer func() { os.Stdin = stdin }()
+
+	go func() {
+		_, _ = w.WriteString("[Event \"Test\"]\n[White \"A\"]\n\n1.e4 *\n")
+		_ = w.Close()
+	}()
+
+	games, err := ParsePGN(Stdin)
+	if err != nil {
+		t.Fatalf("An error occured parsing stdin: %v", err)
+	}
+
+	if len(games) != 1 || games[0].White != "A" {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", games, "A")
+	}
+}
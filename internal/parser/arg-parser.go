@@ -3,13 +3,15 @@ package parser
 import (
 	"fmt"
 	"log/slog"
-	"mime"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"slices"
+	"strconv"
 	"strings"
 
+	"github.com/gavink97/pgn-tools/internal/chess"
 	"github.com/gavink97/pgn-tools/internal/global"
 	"github.com/gavink97/pgn-tools/internal/help"
 )
@@ -42,6 +44,31 @@ func ParseArgs(args []string) {
 		printHelp(argument)
 
 	case "query":
+		ParseFlags(args)
+		if argument == "" {
+			global.Logger.Error("Enter input filepath")
+			os.Exit(1)
+		}
+
+	case "classify":
+		ParseFlags(args)
+		if !VerifyPGNInput(argument) {
+			os.Exit(1)
+		}
+
+	case "extract":
+		ParseFlags(args)
+		if !VerifyPGNInput(argument) {
+			os.Exit(1)
+		}
+
+	case "fix":
+		ParseFlags(args)
+		if !VerifyPGNInput(argument) {
+			os.Exit(1)
+		}
+
+	case "normalize":
 		ParseFlags(args)
 		if !VerifyPGNInput(argument) {
 			os.Exit(1)
@@ -50,6 +77,34 @@ func ParseArgs(args []string) {
 	case "merge":
 		ParseFlags(args)
 
+	case "player":
+		ParseFlags(args)
+		if len(Positional(args)) < 2 {
+			global.Logger.Error("Enter a player name and input filepath")
+			os.Exit(1)
+		}
+
+	case "stats":
+		ParseFlags(args)
+		if argument == "" {
+			global.Logger.Error("Enter input filepath")
+			os.Exit(1)
+		}
+
+	case "tree":
+		ParseFlags(args)
+		if len(Positional(args)) == 0 {
+			global.Logger.Error("Enter input filepath")
+			os.Exit(1)
+		}
+
+	case "validate":
+		ParseFlags(args)
+		if argument == "" {
+			global.Logger.Error("Enter input filepath")
+			os.Exit(1)
+		}
+
 	case "convert":
 		ParseFlags(args)
 
@@ -72,10 +127,143 @@ func ParseFlags(args []string) {
 				global.Output = args[i+1]
 			}
 		}
+		if strings.EqualFold(arg, "--format") && i+1 < len(args) {
+			global.Format = strings.ToLower(args[i+1])
+		}
+		if strings.EqualFold(arg, "--notation") && i+1 < len(args) {
+			global.Notation = strings.ToLower(args[i+1])
+		}
+		if strings.EqualFold(arg, "--input-lang") && i+1 < len(args) {
+			global.InputLang = parseLanguage(args[i+1])
+		}
+		if strings.EqualFold(arg, "--output-lang") && i+1 < len(args) {
+			global.OutputLang = parseLanguage(args[i+1])
+		}
+		if strings.EqualFold(arg, "--columns") && i+1 < len(args) {
+			global.Columns = strings.Split(args[i+1], ",")
+		}
+		if strings.EqualFold(arg, "--compress") {
+			global.Compress = true
+		}
+		if strings.EqualFold(arg, "--input-format") && i+1 < len(args) {
+			global.InputFormat = strings.ToLower(args[i+1])
+
+			err := verifyInputFormat(global.InputFormat)
+			if err != nil {
+				global.Logger.Error(err.Error())
+				os.Exit(1)
+			}
+		}
 		if strings.EqualFold(arg, "--experimental") {
 			global.AllowExperimental = true
 		}
+		if strings.EqualFold(arg, "--stdout") {
+			global.Stdout = true
+		}
+		if strings.EqualFold(arg, "--count") {
+			global.CountOnly = true
+		}
+		if strings.EqualFold(arg, "--split-by") && i+1 < len(args) {
+			global.SplitBy = strings.ToLower(args[i+1])
+		}
+		if strings.EqualFold(arg, "--sort") && i+1 < len(args) {
+			global.Sort = args[i+1]
+		}
+		if strings.EqualFold(arg, "--limit") && i+1 < len(args) {
+			limit, err := strconv.Atoi(args[i+1])
+			if err != nil || limit < 0 {
+				global.Logger.Error(fmt.Sprintf("Invalid limit: %s", args[i+1]))
+				os.Exit(1)
+			}
+			global.Limit = limit
+		}
+		if strings.EqualFold(arg, "--dedup") && i+1 < len(args) {
+			global.Dedup = args[i+1]
+		}
+		if strings.EqualFold(arg, "--reconcile") {
+			global.Reconcile = true
+		}
+		if strings.EqualFold(arg, "--normalize-players") {
+			global.NormalizePlayers = true
+		}
+		if strings.EqualFold(arg, "--player-aliases") && i+1 < len(args) {
+			global.PlayerAliases = args[i+1]
+		}
+		if strings.EqualFold(arg, "--normalize-events") {
+			global.NormalizeEvents = true
+		}
+		if strings.EqualFold(arg, "--event-aliases") && i+1 < len(args) {
+			global.EventAliases = args[i+1]
+		}
+		if strings.EqualFold(arg, "--dedup-report") && i+1 < len(args) {
+			global.DedupReport = args[i+1]
+		}
+		if strings.EqualFold(arg, "--query-file") && i+1 < len(args) {
+			global.QueryFiles = append(global.QueryFiles, args[i+1])
+		}
+		if strings.EqualFold(arg, "--preset") && i+1 < len(args) {
+			global.Presets = append(global.Presets, args[i+1])
+		}
+	}
+}
+
+// parseLanguage validates the language of a lang flag.
+func parseLanguage(name string) string {
+	_, err := chess.ParseLanguage(name)
+	if err != nil {
+		global.Logger.Error(err.Error())
+		os.Exit(1)
+	}
+
+	return strings.ToLower(name)
+}
+
+// flags followed by a value
+var valueFlags = []string{"--output", "-o", "--split-by", "--sort", "--limit", "--query-file", "--preset",
+	"--dedup", "--dedup-report", "--player-aliases",
+	"--event-aliases", "--format", "--columns",
+	"--notation", "--input-lang", "--output-lang",
+	"--input-format"}
+
+// flags followed by a value in a single command, extract uses --fen without
+// a value
+var commandValueFlags = map[string][]string{
+	"extract": {"--ply", "--after-move", "--opcodes"},
+	"fix":     {"--change-log"},
+	"player":  {"--top"},
+	"stats":   {"--top"},
+	"tree":    {"--fen", "--moves", "--depth"},
+}
+
+// extensions of the output formats
+var outputExtensions = []string{".pgn", ".json", ".ndjson", ".jsonl", ".csv", ".tsv", ".epd", ".fen"}
+
+// Positional returns the arguments of the command in args[0] that are not
+// flags or flag values.
+func Positional(args []string) []string {
+	if len(args) == 0 {
+		return nil
+	}
+
+	flags := slices.Concat(valueFlags, commandValueFlags[args[0]])
+	var positional []string
+
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+
+		if slices.ContainsFunc(flags, func(flag string) bool { return strings.EqualFold(flag, arg) }) {
+			i++
+			continue
+		}
+
+		if strings.HasPrefix(arg, "--") {
+			continue
+		}
+
+		positional = append(positional, arg)
 	}
+
+	return positional
 }
 
 func printVersion() {
@@ -89,12 +277,28 @@ func printHelp(command string) {
 		fmt.Println(help.Default)
 	case "bug":
 		fmt.Println(help.Bug)
+	case "classify":
+		fmt.Println(help.Classify)
 	case "convert":
 		fmt.Println(help.Convert)
+	case "extract":
+		fmt.Println(help.Extract)
+	case "fix":
+		fmt.Println(help.Fix)
 	case "merge":
 		fmt.Println(help.Merge)
+	case "normalize":
+		fmt.Println(help.Normalize)
 	case "query":
 		fmt.Println(help.Query)
+	case "player":
+		fmt.Println(help.Player)
+	case "stats":
+		fmt.Println(help.Stats)
+	case "tree":
+		fmt.Println(help.Tree)
+	case "validate":
+		fmt.Println(help.Validate)
 	case "version":
 		fmt.Println(help.Version)
 	default:
@@ -113,22 +317,45 @@ func VerifyPGNInput(file string) bool {
 
 	global.Logger.Debug(fmt.Sprintf("input file: %s", file))
 
-	ext := filepath.Ext(file)
-	filetype := mime.TypeByExtension(ext)
+	// stdin can only be read once so it is not sniffed
+	if file == Stdin {
+		return global.InputFormat == "" || global.InputFormat == PGNInput
+	}
 
-	global.Logger.Debug(fmt.Sprintf("Mime input: %s", filetype))
+	archive, _, isMember := SplitArchivePath(file)
 
-	if !strings.Contains(filetype, "chess") {
-		global.Logger.Error(fmt.Sprintf("Invalid Filetype: %s", file))
+	_, err := os.Stat(archive)
+	if err != nil {
+		global.Logger.Error(fmt.Sprintf("Invalid Filepath: %s", file))
 		return false
 	}
 
-	_, err := os.Stat(file)
-	if err != nil {
-		global.Logger.Error(fmt.Sprintf("Invalid Filepath: %s", file))
+	format := global.InputFormat
+	if format == "" && !isMember && IsArchive(file) {
+		global.Logger.Error(fmt.Sprintf("Zip archives hold multiple databases, name a member such as %s", ArchiveMember(file, "games.pgn")))
+		return false
+	}
+
+	if format == "" {
+		format, err = SniffInput(file)
+		if err != nil {
+			global.Logger.Error(fmt.Sprintf("Unable to read input: %s: %v", file, err))
+			return false
+		}
+	}
+
+	global.Logger.Debug(fmt.Sprintf("Input format: %s", format))
+
+	switch format {
+	case PGNInput:
+		return true
+	case ChessBaseInput:
+		global.Logger.Error(fmt.Sprintf("Chessbase database, use pgn-tools convert: %s", file))
+		return false
+	default:
+		global.Logger.Error(fmt.Sprintf("Invalid Filetype: %s", file))
 		return false
 	}
-	return true
 }
 
 func VerifyPGNOutput(file string) bool {
@@ -139,12 +366,13 @@ func VerifyPGNOutput(file string) bool {
 
 	global.Logger.Debug(fmt.Sprintf("output file: %s", file))
 
+	// only gzip compression can be written
 	ext := filepath.Ext(file)
-	filetype := mime.TypeByExtension(ext)
-
-	global.Logger.Debug(fmt.Sprintf("Mime output: %s", filetype))
+	if strings.EqualFold(ext, GzipExtension) {
+		ext = filepath.Ext(strings.TrimSuffix(file, ext))
+	}
 
-	if !strings.Contains(filetype, "chess") {
+	if !slices.Contains(outputExtensions, strings.ToLower(ext)) {
 		global.Logger.Error(fmt.Sprintf("Invalid Filetype: %s", file))
 		return false
 	}
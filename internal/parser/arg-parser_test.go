@@ -0,0 +1,37 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPositional(t *testing.T) {
+	samples := []struct {
+		args     []string
+		expected []string
+	}{
+		{
+			args:     []string{"extract", "games.pgn", "--fen", "--ply", "10", "--opcodes", "id,c0"},
+			expected: []string{"games.pgn"},
+		},
+		{
+			args:     []string{"tree", "games.pgn", "--fen", "8/8/8/8/8/8/8/K6k w - - 0 1", "--depth", "2", "eco=B90"},
+			expected: []string{"games.pgn", "eco=B90"},
+		},
+		{
+			args:     []string{"stats", "games.pgn", "--top", "5", "year=2023"},
+			expected: []string{"games.pgn", "year=2023"},
+		},
+		{
+			args:     []string{"fix", "games.pgn", "--change-log", "changes.log", "-o", "fixed.pgn"},
+			expected: []string{"games.pgn"},
+		},
+	}
+
+	for _, sample := range samples {
+		result := Positional(sample.args)
+		if !reflect.DeepEqual(result, sample.expected) {
+			t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", result, sample.expected)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/gavink97/pgn-tools/internal/global"
+)
+
+const (
+	Gzip  = "gzip"
+	Bzip2 = "bzip2"
+	Zstd  = "zstd"
+	XZ    = "xz"
+)
+
+// GzipExtension marks an output that is written gzip compressed.
+const GzipExtension = ".gz"
+
+var compressionExtensions = []string{".gz", ".bz2", ".zst", ".xz"}
+
+var compressionMagic = []struct {
+	name  string
+	magic []byte
+}{
+	{Gzip, []byte{0x1f, 0x8b}},
+	{Bzip2, []byte("BZh")},
+	{Zstd, []byte{0x28, 0xb5, 0x2f, 0xfd}},
+	{XZ, []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}},
+}
+
+// Compression names the compression of data from its magic bytes, it returns
+// an empty string for uncompressed data.
+func Compression(header []byte) string {
+	for _, c := range compressionMagic {
+		if bytes.HasPrefix(header, c.magic) {
+			return c.name
+		}
+	}
+
+	return ""
+}
+
+type compressedReader struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (r *compressedReader) Close() error {
+	var err error
+	for _, closer := range r.closers {
+		err = errors.Join(err, closer.Close())
+	}
+	return err
+}
+
+// OpenInput opens a pgn database, a member of a zip archive or stdin for "-",
+// and decompresses it when its magic bytes show gzip or bzip2 compression.
+// Zstandard and xz are detected but can not be read.
+func OpenInput(fileName string) (io.ReadCloser, error) {
+	file, err := openSource(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	return decompress(fileName, file)
+}
+
+// decompress wraps file in the decompressor of its content, file is closed
+// with the returned reader.
+func decompress(fileName string, file io.ReadCloser) (io.ReadCloser, error) {
+	reader := bufio.NewReader(file)
+
+	// a short file fails to peek with io.EOF and is read as is
+	header, err := reader.Peek(6)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, errors.Join(err, file.Close())
+	}
+
+	switch compression := Compression(header); compression {
+	case Gzip:
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, errors.Join(fmt.Errorf("invalid gzip input %s: %w", fileName, err), file.Close())
+		}
+		return &compressedReader{Reader: gz, closers: []io.Closer{gz, file}}, nil
+
+	case Bzip2:
+		return &compressedReader{Reader: bzip2.NewReader(reader), closers: []io.Closer{file}}, nil
+
+	case Zstd, XZ:
+		return nil, errors.Join(fmt.Errorf("unsupported compression %s: %s, decompress the file first", compression, fileName), file.Close())
+
+	default:
+		return &compressedReader{Reader: reader, closers: []io.Closer{file}}, nil
+	}
+}
+
+// TrimCompression removes a compression extension from path, so the
+// extension of the compressed file is left.
+func TrimCompression(path string) string {
+	ext := filepath.Ext(path)
+
+	for _, compressed := range compressionExtensions {
+		if strings.EqualFold(ext, compressed) {
+			return strings.TrimSuffix(path, ext)
+		}
+	}
+
+	return path
+}
+
+// CompressPath appends the gzip extension to path when --compress is set.
+func CompressPath(path string) string {
+	if !global.Compress || path == "" || strings.EqualFold(filepath.Ext(path), GzipExtension) {
+		return path
+	}
+
+	return path + GzipExtension
+}
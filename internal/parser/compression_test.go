@@ -0,0 +1,90 @@
+package parser
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gavink97/pgn-tools/internal/global"
+)
+
+func TestParseCompressedPGN(t *testing.T) {
+	dir := t.TempDir()
+	sample := "[Event \"Test\"]\n[White \"A\"]\n[Black \"B\"]\n[Result \"1-0\"]\n\n1.e4 e5 2.Qh5 Nc6 3.Bc4 Nf6 4.Qxf7# 1-0\n"
+
+	// the extension does not matter, gzip is found from the magic bytes
+	gzipFile := filepath.Join(dir, "game.pgn")
+	f, err := os.Create(gzipFile)
+	if err != nil {
+		t.Fatalf("An error occured creating file: %v", err)
+	}
+
+	gz := gzip.NewWriter(f)
+	_, err = gz.Write([]byte(sample))
+	if err == nil {
+		err = gz.Close()
+	}
+	if err == nil {
+		err = f.Close()
+	}
+	if err != nil {
+		t.Fatalf("An error occured writing gzip: %v", err)
+	}
+
+	games, err := ParsePGN(gzipFile)
+	if err != nil {
+		t.Fatalf("An error occured parsing gzip: %v", err)
+	}
+
+	if len(games) != 1 || games[0].White != "A" || !strings.HasSuffix(games[0].Game, "Qxf7# 1-0") {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", games, sample)
+	}
+
+	games, err = ParsePGN("testdata/game.pgn.bz2")
+	if err != nil {
+		t.Fatalf("An error occured parsing bzip2: %v", err)
+	}
+
+	if len(games) != 1 || games[0].White != "Carlsen, Magnus" {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", games, "Carlsen, Magnus")
+	}
+
+	zstdFile := filepath.Join(dir, "game.pgn.zst")
+	err = os.WriteFile(zstdFile, []byte{0x28, 0xb5, 0x2f, 0xfd, 0x00, 0x00}, 0644)
+	if err != nil {
+		t.Fatalf("An error occured creating file: %v", err)
+	}
+
+	_, err = ParsePGN(zstdFile)
+	if err == nil || !strings.Contains(err.Error(), Zstd) {
+		t.Errorf("Expected an unsupported compression error, got: %v", err)
+	}
+
+	if !VerifyPGNInput("testdata/game.pgn.bz2") {
+		t.Errorf("Expected a compressed pgn to be a valid input")
+	}
+}
+
+func TestCompressPath(t *testing.T) {
+	samples := map[string]string{
+		"games.pgn":    "games.pgn.gz",
+		"games.pgn.gz": "games.pgn.gz",
+		"":             "",
+	}
+
+	global.Compress = true
+	defer func() { global.Compress = false }()
+
+	for path, expected := range samples {
+		result := CompressPath(path)
+		if result != expected {
+			t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", result, expected)
+		}
+	}
+
+	if TrimCompression("games.pgn.bz2") != "games.pgn" {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", TrimCompression("games.pgn.bz2"), "games.pgn")
+	}
+}
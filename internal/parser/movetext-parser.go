@@ -0,0 +1,148 @@
+package parser
+
+import (
+	"strings"
+)
+
+type TokenKind int
+
+const (
+	MoveToken TokenKind = iota
+	MoveNumberToken
+	CommentToken
+	NAGToken
+	VariationStartToken
+	VariationEndToken
+	ResultToken
+)
+
+type Token struct {
+	Kind TokenKind
+	Text string
+}
+
+var results = []string{"1-0", "0-1", "1/2-1/2", "*"}
+
+// ParseMovetext splits pgn movetext into tokens. Comments keep their text
+// without the surrounding braces and suffix annotations such as "!?" are
+// split off the move into their own NAG token.
+func ParseMovetext(movetext string) []Token {
+	var tokens []Token
+	i := 0
+
+	for i < len(movetext) {
+		c := movetext[i]
+
+		switch {
+		case c == ' ' || c == '\n' || c == '\r' || c == '\t':
+			i++
+
+		case c == '{':
+			end := strings.IndexByte(movetext[i:], '}')
+			if end == -1 {
+				end = len(movetext) - i
+			}
+			text := movetext[i+1 : i+end]
+			tokens = append(tokens, Token{Kind: CommentToken, Text: strings.TrimSpace(text)})
+			i += end + 1
+
+		case c == ';':
+			end := strings.IndexByte(movetext[i:], '\n')
+			if end == -1 {
+				end = len(movetext) - i
+			}
+			tokens = append(tokens, Token{Kind: CommentToken, Text: strings.TrimSpace(movetext[i+1 : i+end])})
+			i += end
+
+		case c == '(':
+			tokens = append(tokens, Token{Kind: VariationStartToken, Text: "("})
+			i++
+
+		case c == ')':
+			tokens = append(tokens, Token{Kind: VariationEndToken, Text: ")"})
+			i++
+
+		case c == '$':
+			j := i + 1
+			for j < len(movetext) && movetext[j] >= '0' && movetext[j] <= '9' {
+				j++
+			}
+			tokens = append(tokens, Token{Kind: NAGToken, Text: movetext[i:j]})
+			i = j
+
+		default:
+			j := i
+			for j < len(movetext) && !strings.ContainsRune(" \n\r\t{}();$", rune(movetext[j])) {
+				j++
+			}
+			tokens = appendWord(tokens, movetext[i:j])
+			i = j
+		}
+	}
+
+	return tokens
+}
+
+func appendWord(tokens []Token, word string) []Token {
+	for _, result := range results {
+		if word == result {
+			return append(tokens, Token{Kind: ResultToken, Text: word})
+		}
+	}
+
+	// move numbers can be attached to the move: 1.e4 or 12...Nf6
+	if word[0] >= '0' && word[0] <= '9' {
+		j := 0
+		for j < len(word) && word[j] >= '0' && word[j] <= '9' {
+			j++
+		}
+
+		if j < len(word) && word[j] == '.' {
+			for j < len(word) && word[j] == '.' {
+				j++
+			}
+
+			tokens = append(tokens, Token{Kind: MoveNumberToken, Text: word[:j]})
+			word = word[j:]
+
+			if word == "" {
+				return tokens
+			}
+		}
+	}
+
+	move := strings.TrimRight(word, "!?")
+	if move == "" {
+		return append(tokens, Token{Kind: NAGToken, Text: word})
+	}
+
+	tokens = append(tokens, Token{Kind: MoveToken, Text: move})
+
+	if len(move) < len(word) {
+		tokens = append(tokens, Token{Kind: NAGToken, Text: word[len(move):]})
+	}
+
+	return tokens
+}
+
+// ParseMoves returns the main line moves of the movetext, skipping comments,
+// variations and annotations.
+func ParseMoves(movetext string) []string {
+	var moves []string
+	depth := 0
+
+	for _, token := range ParseMovetext(movetext) {
+		switch token.Kind {
+		case VariationStartToken:
+			depth++
+		case VariationEndToken:
+			depth--
+		case MoveToken:
+			if depth == 0 {
+				moves = append(moves, token.Text)
+			}
+		}
+	}
+
+	return moves
+}
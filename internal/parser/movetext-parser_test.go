@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMovetext(t *testing.T) {
+	sample := `1.e4 {best by test} e5 2. Nf3!? (2. f4 exf4 $6) 2...Nc6 ; rest of line
+3.Bb5 1-0`
+
+	expected := []Token{
+		{Kind: MoveNumberToken, Text: "1."},
+		{Kind: MoveToken, Text: "e4"},
+		{Kind: CommentToken, Text: "best by test"},
+		{Kind: MoveToken, Text: "e5"},
+		{Kind: MoveNumberToken, Text: "2."},
+		{Kind: MoveToken, Text: "Nf3"},
+		{Kind: NAGToken, Text: "!?"},
+		{Kind: VariationStartToken, Text: "("},
+		{Kind: MoveNumberToken, Text: "2."},
+		{Kind: MoveToken, Text: "f4"},
+		{Kind: MoveToken, Text: "exf4"},
+		{Kind: NAGToken, Text: "$6"},
+		{Kind: VariationEndToken, Text: ")"},
+		{Kind: MoveNumberToken, Text: "2..."},
+		{Kind: MoveToken, Text: "Nc6"},
+		{Kind: CommentToken, Text: "rest of line"},
+		{Kind: MoveNumberToken, Text: "3."},
+		{Kind: MoveToken, Text: "Bb5"},
+		{Kind: ResultToken, Text: "1-0"},
+	}
+
+	result := ParseMovetext(sample)
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", result, expected)
+	}
+
+	moves := ParseMoves(sample)
+	expectedMoves := []string{"e4", "e5", "Nf3", "Nc6", "Bb5"}
+
+	if !reflect.DeepEqual(moves, expectedMoves) {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", moves, expectedMoves)
+	}
+}
@@ -1,10 +1,14 @@
 package parser
 
 import (
-	"os"
+	"errors"
+	"io"
 	"strconv"
 	"strings"
 
+	"github.com/gavink97/pgn-tools/internal/chess"
+	"github.com/gavink97/pgn-tools/internal/eco"
+	"github.com/gavink97/pgn-tools/internal/global"
 	"github.com/gavink97/pgn-tools/internal/types"
 )
 
@@ -13,6 +17,7 @@ func NewPGNGame(str string) *types.Game {
 
 	var metadata []string
 	var moves []string
+	inComment := false
 
 	lines := strings.SplitSeq(str, "\n")
 
@@ -23,9 +28,10 @@ func NewPGNGame(str string) *types.Game {
 			continue
 		}
 
-		if strings.HasPrefix(line, "[") {
+		if strings.HasPrefix(line, "[") && !inComment {
 			metadata = append(metadata, line)
 		} else {
+			line, inComment = braceLineComment(line, inComment)
 			moves = append(moves, line)
 		}
 	}
@@ -38,7 +44,7 @@ func NewPGNGame(str string) *types.Game {
 			if len(parts) == 2 {
 				key := parts[0]
 				value := strings.TrimSuffix(parts[1], "\"")
-				value = strings.TrimPrefix(value, "\"")
+				value = unescapeTag(strings.TrimPrefix(value, "\""))
 
 				switch key {
 				case "Event":
@@ -63,6 +69,10 @@ func NewPGNGame(str string) *types.Game {
 					game.BlackElo = elo
 				case "ECO":
 					game.ECO = value
+				case "Opening":
+					game.Opening = value
+				case "Variation":
+					game.Variation = value
 				case "EventDate":
 					game.EventDate = value
 				case "WhiteElo":
@@ -73,6 +83,14 @@ func NewPGNGame(str string) *types.Game {
 					game.WhiteElo = elo
 				case "Source":
 					game.Source = value
+				case "TimeControl":
+					game.TimeControl = value
+				case "FEN":
+					game.FEN = value
+				case "SetUp":
+					// written back whenever the game has a FEN
+				default:
+					game.ExtraTags = append(game.ExtraTags, types.Tag{Name: key, Value: value})
 				}
 			}
 		}
@@ -80,11 +98,106 @@ func NewPGNGame(str string) *types.Game {
 
 	game.Game = strings.Join(moves, " ")
 
+	if language, exists := chess.Languages[global.InputLang]; exists {
+		game.Game = TranslateMovetext(game.Game, language, chess.English)
+	}
+
 	return game
 }
 
+// TranslateMovetext rewrites the piece letters of every move in the movetext
+// from one language to another, comments are left untouched.
+func TranslateMovetext(movetext string, from chess.Language, to chess.Language) string {
+	if from == to {
+		return movetext
+	}
+
+	var sb strings.Builder
+	inComment := false
+	start := -1
+
+	flush := func(end int) {
+		if start >= 0 {
+			sb.WriteString(chess.TranslateSAN(movetext[start:end], from, to))
+			start = -1
+		}
+	}
+
+	for i := 0; i < len(movetext); i++ {
+		c := movetext[i]
+
+		switch {
+		case inComment:
+			inComment = c != '}'
+		case c == '{':
+			flush(i)
+			inComment = true
+		case c == ' ' || c == '\t' || c == '(' || c == ')':
+			flush(i)
+		default:
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+
+		sb.WriteByte(c)
+	}
+
+	flush(len(movetext))
+	return sb.String()
+}
+
+// braceLineComment rewrites a rest of line comment starting with ; into a
+// brace comment so it survives the movetext being joined into one line. It
+// reports whether a brace comment is still open at the end of the line.
+func braceLineComment(line string, inComment bool) (string, bool) {
+	for i := 0; i < len(line); i++ {
+		switch {
+		case inComment:
+			inComment = line[i] != '}'
+		case line[i] == '{':
+			inComment = true
+		case line[i] == ';':
+			text := strings.ReplaceAll(line[i+1:], "}", "")
+			return line[:i] + "{" + strings.TrimSpace(text) + "}", false
+		}
+	}
+
+	return line, inComment
+}
+
+// unescapeTag reverses the backslash escaping of quotes and backslashes in a
+// tag value.
+func unescapeTag(value string) string {
+	if !strings.Contains(value, `\`) {
+		return value
+	}
+
+	var sb strings.Builder
+	escaped := false
+
+	for _, r := range value {
+		if r == '\\' && !escaped {
+			escaped = true
+			continue
+		}
+
+		escaped = false
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
+
 func ParsePGN(fileName string) ([]*types.Game, error) {
-	file, err := os.ReadFile(fileName)
+	reader, err := OpenInput(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := io.ReadAll(reader)
+	err = errors.Join(err, reader.Close())
 	if err != nil {
 		return nil, err
 	}
@@ -93,16 +206,53 @@ func ParsePGN(fileName string) ([]*types.Game, error) {
 	chunks := strings.Split(content, `[Event "`)
 
 	var games []*types.Game
-	for _, chunk := range chunks {
+	for i, chunk := range chunks {
 		chunk = strings.TrimSpace(chunk)
 		if chunk == "" {
 			continue
 		}
 
-		gameText := `[Event "` + chunk
+		// text before the first Event tag is a game without one
+		gameText := chunk
+		if i > 0 {
+			gameText = `[Event "` + chunk
+		}
 		game := NewPGNGame(gameText)
 		games = append(games, game)
 	}
 
 	return games, nil
 }
+
+// ClassifyGame fills the ECO, Opening and Variation tags from the embedded
+// opening classifier. Existing tags are only replaced when overwrite is set,
+// it reports whether the game was changed.
+func ClassifyGame(game *types.Game, overwrite bool) bool {
+	if !overwrite && game.ECO != "" && game.Opening != "" {
+		return false
+	}
+
+	opening, found := eco.Classify(game.FEN, ParseMoves(game.Game))
+	if !found {
+		return false
+	}
+
+	changed := false
+
+	if overwrite || game.ECO == "" {
+		changed = changed || game.ECO != opening.ECO
+		game.ECO = opening.ECO
+	}
+
+	if overwrite || game.Opening == "" {
+		changed = changed || game.Opening != opening.Opening
+		game.Opening = opening.Opening
+	}
+
+	if overwrite || game.Variation == "" {
+		changed = changed || game.Variation != opening.Variation
+		game.Variation = opening.Variation
+	}
+
+	return changed
+}
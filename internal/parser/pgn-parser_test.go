@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/gavink97/pgn-tools/internal/chess"
 	"github.com/gavink97/pgn-tools/internal/global"
 	"github.com/gavink97/pgn-tools/internal/types"
 )
@@ -82,6 +83,8 @@ func TestNewPGNGame(t *testing.T) {
 [ECO "C65"]
 [EventDate "2012.07.23"]
 [WhiteElo "2837"]
+[Annotator "Bacrot, Etienne"]
+[PlyCount "124"]
 1.e4 e5 2.Nf3 Nc6 3.Bb5 Nf6 4.d3 Bc5 5.Bxc6 dxc6 6.Nbd2 Be6 7.b3 Ng4 8.O-O f6 9.Qe2 Qd7 10.Nc4 g5 11.Rb1 b5 12.Ne3 h5 13.c3 Qh7 14.d4 Bb6 15.Nc2 O-O-O 16.a4 exd4 17.Nfxd4 Bd7 18.b4 Rhe8 19.Re1 a6 20.Ra1 Kb7 21.axb5 axb5 22.c4 bxc4 23.Qxc4 Ra8 24.Rxa8 Rxa8 25.Bd2 h4 26.Qc3 h3 27.Qxh3 Qxh3 28.gxh3 Ne5 29.Kg2 Rh8 30.Nf5 Ng6 31.Ncd4 Bxd4 32.Nxd4 Rxh3 33.Re3 Nf4+ 34.Kg1 Rh8 35.Nb3 Kb6 36.Nc5 Bc8 37.Ra3 Bh3 38.f3 Rd8 39.Bxf4 gxf4 40.Nd3 Rg8+ 41.Kf2 Rg2+ 42.Ke1 Rxh2 43.Nxf4 Bc8 44.Ra8 Kb7 45.Ra5 Kb6 46.Nd3 Rc2 47.Rh5 Ra2 48.Rh8 Ba6 49.Nc5 Re2+ 50.Kd1 Rf2 51.Ra8 Bb5 52.Rb8+ Ka7 53.Rb7+ Ka8 54.Rxc7 Rxf3 55.Kd2 Kb8 56.Rf7 Kc8 57.Kc2 Rf1 58.Nb7 Rf3 59.Nd6+ Kd8 60.Kb2 Ba4 61.Nb7+ Ke8 62.Nd6+ Kd8 1/2-1/2`
 
 	expected := &types.Game{
@@ -96,6 +99,7 @@ func TestNewPGNGame(t *testing.T) {
 		ECO:       "C65",
 		EventDate: "2012.07.23",
 		WhiteElo:  2837,
+		ExtraTags: []types.Tag{{Name: "Annotator", Value: "Bacrot, Etienne"}, {Name: "PlyCount", Value: "124"}},
 		Game:      `1.e4 e5 2.Nf3 Nc6 3.Bb5 Nf6 4.d3 Bc5 5.Bxc6 dxc6 6.Nbd2 Be6 7.b3 Ng4 8.O-O f6 9.Qe2 Qd7 10.Nc4 g5 11.Rb1 b5 12.Ne3 h5 13.c3 Qh7 14.d4 Bb6 15.Nc2 O-O-O 16.a4 exd4 17.Nfxd4 Bd7 18.b4 Rhe8 19.Re1 a6 20.Ra1 Kb7 21.axb5 axb5 22.c4 bxc4 23.Qxc4 Ra8 24.Rxa8 Rxa8 25.Bd2 h4 26.Qc3 h3 27.Qxh3 Qxh3 28.gxh3 Ne5 29.Kg2 Rh8 30.Nf5 Ng6 31.Ncd4 Bxd4 32.Nxd4 Rxh3 33.Re3 Nf4+ 34.Kg1 Rh8 35.Nb3 Kb6 36.Nc5 Bc8 37.Ra3 Bh3 38.f3 Rd8 39.Bxf4 gxf4 40.Nd3 Rg8+ 41.Kf2 Rg2+ 42.Ke1 Rxh2 43.Nxf4 Bc8 44.Ra8 Kb7 45.Ra5 Kb6 46.Nd3 Rc2 47.Rh5 Ra2 48.Rh8 Ba6 49.Nc5 Re2+ 50.Kd1 Rf2 51.Ra8 Bb5 52.Rb8+ Ka7 53.Rb7+ Ka8 54.Rxc7 Rxf3 55.Kd2 Kb8 56.Rf7 Kc8 57.Kc2 Rf1 58.Nb7 Rf3 59.Nd6+ Kd8 60.Kb2 Ba4 61.Nb7+ Ke8 62.Nd6+ Kd8 1/2-1/2`,
 	}
 
@@ -105,3 +109,23 @@ func TestNewPGNGame(t *testing.T) {
 		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", result, expected)
 	}
 }
+
+func TestTranslateMovetext(t *testing.T) {
+	german := chess.Languages["de"]
+
+	movetext := "1.e4 e5 2.Sf3 {Der Springer} (2.Lc4 Sf6) 2...Sc6 3.Lb5 a6 4.0-0 Dh4 5.Txe1 e1=D 1-0"
+	expected := "1.e4 e5 2.Nf3 {Der Springer} (2.Bc4 Nf6) 2...Nc6 3.Bb5 a6 4.0-0 Qh4 5.Rxe1 e1=Q 1-0"
+
+	result := TranslateMovetext(movetext, german, chess.English)
+	if result != expected {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", result, expected)
+	}
+
+	global.InputLang = "de"
+	defer func() { global.InputLang = "" }()
+
+	game := NewPGNGame("[Event \"Test\"]\n" + movetext)
+	if game.Game != expected {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", game.Game, expected)
+	}
+}
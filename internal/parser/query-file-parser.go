@@ -0,0 +1,113 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gavink97/pgn-tools/internal/global"
+)
+
+// ParseQueryFile loads named queries from a file where every query is written
+// as "name: key=value, key=value". Lines starting with whitespace continue the
+// previous query and lines starting with # are comments.
+func ParseQueryFile(fileName string) ([]*Query, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		err := file.Close()
+		if err != nil {
+			global.Logger.Warn(fmt.Sprintf("An error occured closing %s", fileName))
+		}
+	}()
+
+	var names []string
+	var keys []string
+
+	scanner := bufio.NewScanner(file)
+	lineNo := 0
+
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if line[0] == ' ' || line[0] == '\t' {
+			if len(keys) == 0 {
+				return nil, fmt.Errorf("%s:%d: continuation without a query", fileName, lineNo)
+			}
+
+			keys[len(keys)-1] += "," + trimmed
+			continue
+		}
+
+		name, conditions, found := strings.Cut(trimmed, ":")
+		name = strings.TrimSpace(name)
+
+		if !found || name == "" || strings.ContainsAny(name, "=<>!,") {
+			return nil, fmt.Errorf("%s:%d: expected 'name: key=value', got: %s", fileName, lineNo, trimmed)
+		}
+
+		names = append(names, name)
+		keys = append(keys, conditions)
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	var queries []*Query
+
+	for i, name := range names {
+		query, err := ParseQuery(keys[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s: query %s: %v", fileName, name, err)
+		}
+
+		query.Name = name
+		queries = append(queries, query)
+	}
+
+	return queries, nil
+}
+
+// PresetFile is the user config holding named query presets, it uses the
+// same format as a query file.
+func PresetFile() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, "pgn-tools", "presets.q"), nil
+}
+
+func LoadPreset(name string) (*Query, error) {
+	presetFile, err := PresetFile()
+	if err != nil {
+		return nil, err
+	}
+
+	presets, err := ParseQueryFile(presetFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load presets: %v", err)
+	}
+
+	for _, preset := range presets {
+		if strings.EqualFold(preset.Name, name) {
+			return preset, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown preset %s in %s", name, presetFile)
+}
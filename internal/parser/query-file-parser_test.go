@@ -0,0 +1,59 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseQueryFile(t *testing.T) {
+	sample := `# opponent preparation
+elite: elo>=2700
+najdorf: eco>=B90,
+	eco<=B99, player!=carlsen
+`
+
+	file := filepath.Join(t.TempDir(), "prep.q")
+
+	err := os.WriteFile(file, []byte(sample), 0600)
+	if err != nil {
+		t.Fatalf("An error occured writing query file: %v", err)
+	}
+
+	expected := []*Query{
+		{
+			Name: "elite",
+			Conditions: []QueryCondition{
+				{Key: "elo", Op: ">=", Value: "2700"},
+			},
+		},
+		{
+			Name: "najdorf",
+			Conditions: []QueryCondition{
+				{Key: "eco", Op: ">=", Value: "B90"},
+				{Key: "eco", Op: "<=", Value: "B99"},
+				{Key: "player", Op: "!=", Value: "carlsen"},
+			},
+		},
+	}
+
+	result, err := ParseQueryFile(file)
+	if err != nil {
+		t.Fatalf("An error occured parsing query file: %v", err)
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", result, expected)
+	}
+
+	err = os.WriteFile(file, []byte("elo>=2700\n"), 0600)
+	if err != nil {
+		t.Fatalf("An error occured writing query file: %v", err)
+	}
+
+	_, err = ParseQueryFile(file)
+	if err == nil {
+		t.Errorf("Expected an error for a query without a name")
+	}
+}
@@ -19,6 +19,7 @@ type QueryCondition struct {
 }
 
 type Query struct {
+	Name       string
 	Conditions []QueryCondition
 }
 
@@ -180,6 +181,13 @@ var computedFields = map[string]func(*types.Game, *QueryCondition) (any, error){
 			return 0, nil
 		}
 	},
+	"avgelo": func(g *types.Game, qc *QueryCondition) (any, error) {
+		if g.WhiteElo > 0 && g.BlackElo > 0 {
+			return (g.WhiteElo + g.BlackElo) / 2, nil
+		} else {
+			return 0, nil
+		}
+	},
 	"player": func(g *types.Game, qc *QueryCondition) (any, error) {
 		if qc.Op == "!=" {
 			for _, player := range []string{g.White, g.Black} {
@@ -206,13 +214,143 @@ var computedFields = map[string]func(*types.Game, *QueryCondition) (any, error){
 
 		return white || black, nil
 	},
+	"eco": func(g *types.Game, qc *QueryCondition) (any, error) {
+		code := g.ECO
+		if code == "" {
+			code = classifiedGame(g).ECO
+		}
+
+		switch qc.Op {
+		case "=", "!=":
+			return code, nil
+		default:
+			return compareECO(code, qc)
+		}
+	},
+	"opening": func(g *types.Game, qc *QueryCondition) (any, error) {
+		return classifiedGame(g).Opening, nil
+	},
+	"variation": func(g *types.Game, qc *QueryCondition) (any, error) {
+		return classifiedGame(g).Variation, nil
+	},
+	"year": func(g *types.Game, qc *QueryCondition) (any, error) {
+		return gameYear(g), nil
+	},
+	"plies": func(g *types.Game, qc *QueryCondition) (any, error) {
+		return len(ParseMoves(g.Game)), nil
+	},
+}
+
+func gameYear(g *types.Game) int {
+	if len(g.Date) < 4 {
+		return 0
+	}
+
+	year, err := strconv.Atoi(g.Date[:4])
+	if err != nil {
+		return 0
+	}
+
+	return year
+}
+
+// GameValues returns the values of a query key for the game, player yields
+// both players while every other key yields a single value.
+func GameValues(game *types.Game, key string) ([]string, error) {
+	key = strings.ToLower(key)
+
+	switch key {
+	case "player":
+		return []string{game.White, game.Black}, nil
+	case "year":
+		if year := gameYear(game); year != 0 {
+			return []string{strconv.Itoa(year)}, nil
+		}
+		return []string{""}, nil
+	}
+
+	computedFunc, exists := computedFields[key]
+	if exists {
+		value, err := computedFunc(game, &QueryCondition{Key: key, Op: "="})
+		if err != nil {
+			return nil, err
+		}
+
+		return []string{fmt.Sprint(value)}, nil
+	}
+
+	field, found := FindField(reflect.ValueOf(game).Elem(), key)
+	if !found {
+		return nil, fmt.Errorf("unknown field: %s", key)
+	}
+
+	return []string{fmt.Sprint(field.Interface())}, nil
+}
+
+// classifiedGame returns a copy of the game with missing opening tags filled
+// in by the classifier, the matched game itself is left untouched.
+func classifiedGame(g *types.Game) *types.Game {
+	if g.ECO != "" && g.Opening != "" {
+		return g
+	}
+
+	classified := *g
+	ClassifyGame(&classified, false)
+	return &classified
+}
+
+// compareECO orders eco codes on the length of the query value so that
+// "eco<=B9" includes every code from B90 to B99.
+func compareECO(code string, qc *QueryCondition) (bool, error) {
+	if code == "" {
+		return false, nil
+	}
+
+	value := strings.ToUpper(qc.Value)
+	code = strings.ToUpper(code)
+	code = code[:min(len(code), len(value))]
+
+	switch qc.Op {
+	case ">":
+		return code > value, nil
+	case "<":
+		return code < value, nil
+	case ">=":
+		return code >= value, nil
+	case "<=":
+		return code <= value, nil
+	default:
+		return false, fmt.Errorf("unsupported operator: %s", qc.Op)
+	}
 }
 
 func (query *Query) WriteTo(input string) string {
+	if query.Name != "" {
+		return OutputPath(input, query.Name)
+	}
+
+	var kvPairs []string
+	for _, c := range query.Conditions {
+		kv := strings.Join([]string{c.Key, c.Op, c.Value}, "")
+		kvPairs = append(kvPairs, kv)
+	}
+
+	return OutputPath(input, strings.Join(kvPairs, "_"))
+}
+
+// OutputPath resolves the output of a command, it uses the global output when
+// set and otherwise derives a path next to the input with the suffix appended.
+func OutputPath(input string, suffix string) string {
 	output := global.Output
-	ext := filepath.Ext(input)
-	dir, file := filepath.Split(input)
-	baseName := strings.TrimSuffix(file, ext)
+	dir, file := filepath.Split(localPath(input))
+
+	// a gzip compressed input keeps its compression, other compressions are
+	// written uncompressed
+	ext := filepath.Ext(TrimCompression(file))
+	baseName := strings.TrimSuffix(TrimCompression(file), ext)
+	if strings.EqualFold(filepath.Ext(file), GzipExtension) {
+		ext += GzipExtension
+	}
 
 	fallback := func() {
 		output = fmt.Sprintf("%s%s_modified%s", dir, baseName, ext)
@@ -220,20 +358,8 @@ func (query *Query) WriteTo(input string) string {
 	}
 
 	if output == "" {
-		output_name := ""
-
-		var kvPairs []string
-		for _, c := range query.Conditions {
-			kv := strings.Join([]string{c.Key, c.Op, c.Value}, "")
-			kvPairs = append(kvPairs, kv)
-		}
-
-		if len(kvPairs) > 0 {
-			output_name = strings.Join(kvPairs, "_")
-		}
-
-		if output_name != "" {
-			output = fmt.Sprintf("%s%s_%s%s", dir, baseName, output_name, ext)
+		if suffix != "" {
+			output = fmt.Sprintf("%s%s_%s%s", dir, baseName, suffix, ext)
 		} else {
 			fallback()
 		}
@@ -245,7 +371,7 @@ func (query *Query) WriteTo(input string) string {
 			global.Logger.Warn(fmt.Sprintf("Error getting user home directory: %v", err))
 			fallback()
 		} else {
-			output = filepath.Join(homeDir, global.Output[2:])
+			output = filepath.Join(homeDir, output[2:])
 		}
 	}
 
@@ -259,5 +385,5 @@ func (query *Query) WriteTo(input string) string {
 		}
 	}
 
-	return output
+	return CompressPath(output)
 }
@@ -88,6 +88,55 @@ func TestMatch(t *testing.T) {
 
 }
 
+func TestECORange(t *testing.T) {
+	samples := map[string][]bool{
+		"eco>=C60,eco<=C99": {true, false},
+		"eco<=A":            {false, true},
+		"eco>A59":           {true, false},
+		"eco=C6":            {true, false},
+		"opening=ruy lopez": {true, false},
+	}
+
+	for keys, expected := range samples {
+		query, err := ParseQuery(keys)
+		if err != nil {
+			t.Errorf("An error occured parsing query: %v", err)
+			continue
+		}
+
+		for index, game := range sampleGames {
+			result, err := query.Match(game)
+			if err != nil {
+				t.Errorf("An error occured matching sample games: %v", err)
+			}
+
+			if result != expected[index] {
+				t.Errorf("Incorrect Result for %s: \nresult: %v \nexpected: %v", keys, result, expected[index])
+			}
+		}
+	}
+}
+
+func TestGameValues(t *testing.T) {
+	samples := map[string][]string{
+		"player": {"Carlsen, Magnus", "Bacrot, Etienne"},
+		"year":   {"2012"},
+		"elo":    {"2713"},
+		"event":  {"45th Biel GM"},
+	}
+
+	for key, expected := range samples {
+		result, err := GameValues(sampleGames[0], key)
+		if err != nil {
+			t.Errorf("An error occured getting values for %s: %v", key, err)
+		}
+
+		if !reflect.DeepEqual(result, expected) {
+			t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", result, expected)
+		}
+	}
+}
+
 func TestFindField(t *testing.T) {
 	query := Query{
 		Conditions: []QueryCondition{
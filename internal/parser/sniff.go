@@ -0,0 +1,80 @@
+package parser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+)
+
+const (
+	PGNInput       = "pgn"
+	ChessBaseInput = "chessbase"
+)
+
+var InputFormats = []string{PGNInput, ChessBaseInput}
+
+// sniffSize is the number of bytes read to find the format of an input.
+const sniffSize = 4096
+
+var tagPairPattern = regexp.MustCompile(`(?m)^\s*\[[A-Za-z0-9_]+\s+"`)
+
+// the first bytes of a chessbase header file
+var chessBaseSignatures = [][]byte{
+	[]byte("\x00\x00\x2c\x00\x2e\x01"),
+	[]byte("\x00\x00\x24\x00\x2e\x01"),
+}
+
+// SniffFormat names the format of an input from its first bytes, pgn
+// databases are found by a tag pair and chessbase databases by the header
+// signature. It returns an empty string for unknown content.
+func SniffFormat(header []byte) string {
+	for _, signature := range chessBaseSignatures {
+		if bytes.HasPrefix(header, signature) {
+			return ChessBaseInput
+		}
+	}
+
+	if tagPairPattern.Match(header) {
+		return PGNInput
+	}
+
+	return ""
+}
+
+// SniffInput reads the start of an input after decompressing it and names
+// its format.
+func SniffInput(fileName string) (string, error) {
+	reader, err := OpenInput(fileName)
+	if err != nil {
+		return "", err
+	}
+
+	return sniff(reader)
+}
+
+// sniff names the format of the content of reader and closes it.
+func sniff(reader io.ReadCloser) (string, error) {
+	header := make([]byte, sniffSize)
+	n, err := io.ReadFull(reader, header)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		err = nil
+	}
+
+	err = errors.Join(err, reader.Close())
+	if err != nil {
+		return "", err
+	}
+
+	return SniffFormat(header[:n]), nil
+}
+
+func verifyInputFormat(format string) error {
+	if format == "" || slices.Contains(InputFormats, format) {
+		return nil
+	}
+
+	return fmt.Errorf("unknown input format: %s, expected one of %v", format, InputFormats)
+}
@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gavink97/pgn-tools/internal/global"
+)
+
+func TestSniffFormat(t *testing.T) {
+	samples := map[string]string{
+		"[Event \"Test\"]\n1.e4 *":                  PGNInput,
+		"% exported games\n\n  [Site \"Wijk\"]\n":   PGNInput,
+		"\x00\x00\x2c\x00\x2e\x01\x00\x00":          ChessBaseInput,
+		"\x00\x00\x24\x00\x2e\x01":                  ChessBaseInput,
+		"1.e4 e5 2.Nf3 *":                           "",
+		"[link](https://example.com) in a readme\n": "",
+	}
+
+	for header, expected := range samples {
+		result := SniffFormat([]byte(header))
+		if result != expected {
+			t.Errorf("Incorrect Result for %q: \nresult: %v \nexpected: %v", header, result, expected)
+		}
+	}
+}
+
+func TestVerifyPGNInputContent(t *testing.T) {
+	dir := t.TempDir()
+
+	// a pgn database with an unknown extension is found by its tag pairs
+	games := filepath.Join(dir, "games.txt")
+	err := os.WriteFile(games, []byte("[Event \"Test\"]\n\n1.e4 *\n"), 0644)
+	if err != nil {
+		t.Fatalf("An error occured writing file: %v", err)
+	}
+
+	movetext := filepath.Join(dir, "movetext.pgn")
+	err = os.WriteFile(movetext, []byte("1.e4 e5 *\n"), 0644)
+	if err != nil {
+		t.Fatalf("An error occured writing file: %v", err)
+	}
+
+	if !VerifyPGNInput(games) {
+		t.Errorf("Expected %s to be a valid input", games)
+	}
+
+	if VerifyPGNInput(movetext) {
+		t.Errorf("Expected %s to be an invalid input", movetext)
+	}
+
+	global.InputFormat = PGNInput
+	defer func() { global.InputFormat = "" }()
+
+	if !VerifyPGNInput(movetext) {
+		t.Errorf("Expected %s to be a valid input with the input format given", movetext)
+	}
+
+	result, err := ParsePGN(movetext)
+	if err != nil {
+		t.Fatalf("An error occured parsing pgn: %v", err)
+	}
+
+	if len(result) != 1 || result[0].Event != "" || result[0].Game != "1.e4 e5 *" {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", result, "1.e4 e5 *")
+	}
+}
+
+func TestVerifyPGNOutput(t *testing.T) {
+	for _, output := range []string{"out.pgn", "OUT.PGN", "out.pgn.gz", "out.json", "out.csv.gz", "out.epd"} {
+		if !VerifyPGNOutput(output) {
+			t.Errorf("Expected %s to be a valid output", output)
+		}
+	}
+
+	for _, output := range []string{"out.txt", "out.gz", "out.zip", ""} {
+		if VerifyPGNOutput(output) {
+			t.Errorf("Expected %s to be an invalid output", output)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gavink97/pgn-tools/internal/types"
+)
+
+type SortKey struct {
+	Key  string
+	Desc bool
+}
+
+type Sort struct {
+	Keys []SortKey
+}
+
+// ParseSort compiles a sort specification such as "date desc,avgelo desc",
+// keys sort ascending unless followed by desc.
+func ParseSort(spec string) (*Sort, error) {
+	sort := &Sort{}
+
+	for part := range strings.SplitSeq(spec, ",") {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if len(fields) > 2 {
+			return nil, fmt.Errorf("invalid sort key: %s", part)
+		}
+
+		key := SortKey{Key: strings.ToLower(fields[0])}
+
+		if len(fields) == 2 {
+			switch strings.ToLower(fields[1]) {
+			case "asc":
+			case "desc":
+				key.Desc = true
+			default:
+				return nil, fmt.Errorf("invalid sort direction: %s", fields[1])
+			}
+		}
+
+		_, err := sortValue(&types.Game{}, key.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		sort.Keys = append(sort.Keys, key)
+	}
+
+	if len(sort.Keys) == 0 {
+		return nil, fmt.Errorf("empty sort: %s", spec)
+	}
+
+	return sort, nil
+}
+
+// Compare orders two games by the sort keys, games missing a value are
+// ordered after games that have one regardless of the direction.
+func (s *Sort) Compare(a *types.Game, b *types.Game) int {
+	return s.CompareValues(s.Values(a), s.Values(b))
+}
+
+// Values returns the sort values of a game for CompareValues. Keys such as
+// eco replay the moves, so sorting many games computes the values once per
+// game instead of on every comparison.
+func (s *Sort) Values(game *types.Game) []any {
+	values := make([]any, len(s.Keys))
+	for i, key := range s.Keys {
+		values[i], _ = sortValue(game, key.Key)
+	}
+
+	return values
+}
+
+// CompareValues orders the sort values of two games like Compare.
+func (s *Sort) CompareValues(a []any, b []any) int {
+	for i, key := range s.Keys {
+		aValue, bValue := a[i], b[i]
+
+		aMissing := aValue == "" || aValue == 0
+		bMissing := bValue == "" || bValue == 0
+
+		switch {
+		case aMissing && bMissing:
+			continue
+		case aMissing:
+			return 1
+		case bMissing:
+			return -1
+		}
+
+		cmp := 0
+		switch av := aValue.(type) {
+		case int:
+			cmp = av - bValue.(int)
+		case string:
+			cmp = strings.Compare(av, bValue.(string))
+		}
+
+		if cmp == 0 {
+			continue
+		}
+
+		if key.Desc {
+			return -cmp
+		}
+		return cmp
+	}
+
+	return 0
+}
+
+func sortValue(game *types.Game, key string) (any, error) {
+	switch key {
+	case "date", "eventdate":
+		field, _ := FindField(reflect.ValueOf(game).Elem(), key)
+		return DateKey(field.String()), nil
+	case "round":
+		return roundValue(game.Round), nil
+	case "player":
+		if game.White == "" && game.Black == "" {
+			return "", nil
+		}
+		return strings.ToLower(game.White + "\x00" + game.Black), nil
+	}
+
+	computedFunc, exists := computedFields[key]
+	if exists {
+		value, err := computedFunc(game, &QueryCondition{Key: key, Op: "="})
+		if err != nil {
+			return nil, err
+		}
+
+		switch v := value.(type) {
+		case int:
+			return v, nil
+		case string:
+			return strings.ToLower(v), nil
+		default:
+			return nil, fmt.Errorf("unsupported sort key: %s", key)
+		}
+	}
+
+	field, found := FindField(reflect.ValueOf(game).Elem(), key)
+	if !found {
+		return nil, fmt.Errorf("unknown field: %s", key)
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		return strings.ToLower(field.String()), nil
+	case reflect.Int:
+		return int(field.Int()), nil
+	default:
+		return nil, fmt.Errorf("unsupported sort key: %s", key)
+	}
+}
+
+// DateKey makes dates with unknown months or days comparable, dates without
+// a year have no key.
+func DateKey(date string) string {
+	if len(date) < 4 || strings.Contains(date[:4], "?") {
+		return ""
+	}
+	return strings.ReplaceAll(date, "?", "0")
+}
+
+// roundValue pads every numeric part of a round so that "2.1" sorts before
+// "10.1", unknown rounds have no value.
+func roundValue(round string) string {
+	if round == "" || round == "?" || round == "-" {
+		return ""
+	}
+
+	parts := strings.Split(round, ".")
+	for i, part := range parts {
+		if _, err := strconv.Atoi(part); err == nil {
+			parts[i] = fmt.Sprintf("%06s", part)
+		}
+	}
+
+	return strings.ToLower(strings.Join(parts, "."))
+}
@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/gavink97/pgn-tools/internal/types"
+)
+
+func TestParseSort(t *testing.T) {
+	expected := &Sort{
+		Keys: []SortKey{
+			{Key: "date", Desc: true},
+			{Key: "avgelo", Desc: true},
+			{Key: "event"},
+		},
+	}
+
+	result, err := ParseSort("date desc, avgelo DESC, event asc")
+	if err != nil {
+		t.Fatalf("An error occured parsing sort: %v", err)
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", result, expected)
+	}
+
+	for _, invalid := range []string{"opening sideways", "date sideways", "unknown"} {
+		_, err := ParseSort(invalid)
+		if err == nil {
+			t.Errorf("Expected an error parsing sort: %s", invalid)
+		}
+	}
+}
+
+func TestSortCompare(t *testing.T) {
+	sort, err := ParseSort("avgelo desc")
+	if err != nil {
+		t.Fatalf("An error occured parsing sort: %v", err)
+	}
+
+	// Carlsen - Bacrot has the higher average rating
+	result := sort.Compare(sampleGames[0], sampleGames[1])
+	if result >= 0 {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", result, "< 0")
+	}
+}
+
+func TestSortRound(t *testing.T) {
+	sort, err := ParseSort("event, round")
+	if err != nil {
+		t.Fatalf("An error occured parsing sort: %v", err)
+	}
+
+	rounds := []string{"10.1", "2.1", "?", "2.2", "1"}
+	games := make([]*types.Game, len(rounds))
+	for i, round := range rounds {
+		games[i] = &types.Game{Event: "Tata Steel Masters", Round: round}
+	}
+
+	slices.SortStableFunc(games, sort.Compare)
+
+	var result []string
+	for _, game := range games {
+		result = append(result, game.Round)
+	}
+
+	expected := []string{"1", "2.1", "2.2", "10.1", "?"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", result, expected)
+	}
+}
+
+func TestSortDate(t *testing.T) {
+	sort, err := ParseSort("date asc")
+	if err != nil {
+		t.Fatalf("An error occured parsing sort: %v", err)
+	}
+
+	dates := []string{"????.??.??", "2023.01.14", "", "2021.??.??", "2023.01.??"}
+	games := make([]*types.Game, len(dates))
+	for i, date := range dates {
+		games[i] = &types.Game{Date: date}
+	}
+
+	slices.SortStableFunc(games, sort.Compare)
+
+	var result []string
+	for _, game := range games {
+		result = append(result, game.Date)
+	}
+
+	expected := []string{"2021.??.??", "2023.01.??", "2023.01.14", "????.??.??", ""}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", result, expected)
+	}
+}
@@ -0,0 +1,466 @@
+package player
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gavink97/pgn-tools/internal/fix"
+	"github.com/gavink97/pgn-tools/internal/parser"
+	"github.com/gavink97/pgn-tools/internal/types"
+)
+
+// DefaultTop is the number of openings, best wins and worst losses listed.
+const DefaultTop = 10
+
+// OpeningPlies is the number of half moves an opening is grouped by after
+// its ECO code.
+const OpeningPlies = 4
+
+const (
+	Bullet         = "bullet"
+	Blitz          = "blitz"
+	Rapid          = "rapid"
+	Classical      = "classical"
+	Correspondence = "correspondence"
+	Unknown        = "unknown"
+)
+
+var TimeControls = []string{Bullet, Blitz, Rapid, Classical, Correspondence, Unknown}
+
+// Score counts the results of a player, Percent is the share of the points
+// available.
+type Score struct {
+	Games   int     `json:"games"`
+	Wins    int     `json:"wins"`
+	Draws   int     `json:"draws"`
+	Losses  int     `json:"losses"`
+	Points  float64 `json:"points"`
+	Percent float64 `json:"percent"`
+}
+
+// Line is the score of the games sharing an opening, a year or a time control.
+type Line struct {
+	Name string `json:"name"`
+	Score
+}
+
+// GameRef describes a game of the player from their side of the board.
+type GameRef struct {
+	Date        string `json:"date"`
+	Event       string `json:"event"`
+	Color       string `json:"color"`
+	Opponent    string `json:"opponent"`
+	OpponentElo int    `json:"opponent_elo,omitempty"`
+	Result      string `json:"result"`
+	ECO         string `json:"eco,omitempty"`
+}
+
+// Report summarizes the games of a player. The performance rating is computed
+// from the games against rated opponents only.
+type Report struct {
+	Player             string    `json:"player"`
+	Total              Score     `json:"total"`
+	White              Score     `json:"white"`
+	Black              Score     `json:"black"`
+	RatedGames         int       `json:"rated_games"`
+	AverageOpponentElo float64   `json:"average_opponent_elo"`
+	Performance        int       `json:"performance_rating"`
+	Openings           []Line    `json:"openings"`
+	Years              []Line    `json:"years"`
+	TimeControls       []Line    `json:"time_controls"`
+	BestWins           []GameRef `json:"best_wins"`
+	WorstLosses        []GameRef `json:"worst_losses"`
+}
+
+// Collector gathers the report of a player from games added one at a time.
+// Games the player did not play, played against themselves or that have no
+// result are skipped.
+type Collector struct {
+	Name         string
+	Top          int
+	names        map[string]int
+	total        Score
+	white        Score
+	black        Score
+	ratedElo     int
+	rated        Score
+	openings     map[string]*Score
+	years        map[string]*Score
+	timeControls map[string]*Score
+	wins         []GameRef
+	losses       []GameRef
+}
+
+func NewCollector(name string, top int) *Collector {
+	if top <= 0 {
+		top = DefaultTop
+	}
+
+	return &Collector{
+		Name:         name,
+		Top:          top,
+		names:        map[string]int{},
+		openings:     map[string]*Score{},
+		years:        map[string]*Score{},
+		timeControls: map[string]*Score{},
+	}
+}
+
+func (c *Collector) Add(game *types.Game) {
+	white, found := c.side(game)
+	if !found {
+		return
+	}
+
+	result, valid := fix.NormalizeResult(game.Result)
+	if !valid || result == "*" {
+		return
+	}
+
+	ref := GameRef{Date: game.Date, Event: game.Event, Result: result}
+	var points float64
+
+	if white {
+		c.names[game.White]++
+		ref.Color, ref.Opponent, ref.OpponentElo = "white", game.Black, game.BlackElo
+		points = whitePoints(result)
+		c.white.add(points)
+	} else {
+		c.names[game.Black]++
+		ref.Color, ref.Opponent, ref.OpponentElo = "black", game.White, game.WhiteElo
+		points = 1 - whitePoints(result)
+		c.black.add(points)
+	}
+
+	c.total.add(points)
+
+	if ref.OpponentElo > 0 {
+		c.ratedElo += ref.OpponentElo
+		c.rated.add(points)
+	}
+
+	eco, err := parser.GameValues(game, "eco")
+	if err == nil {
+		ref.ECO = eco[0]
+	}
+	addTo(c.openings, opening(ref.ECO, game.Game), points)
+
+	year := Unknown
+	if years, err := parser.GameValues(game, "year"); err == nil && years[0] != "" {
+		year = years[0]
+	}
+	addTo(c.years, year, points)
+
+	addTo(c.timeControls, TimeControl(game.TimeControl), points)
+
+	switch points {
+	case 1:
+		c.wins = append(c.wins, ref)
+	case 0:
+		c.losses = append(c.losses, ref)
+	}
+}
+
+func (c *Collector) Report() *Report {
+	r := &Report{
+		Player:       c.Name,
+		Total:        c.total.finish(),
+		White:        c.white.finish(),
+		Black:        c.black.finish(),
+		RatedGames:   c.rated.Games,
+		Openings:     lines(c.openings),
+		Years:        lines(c.years),
+		TimeControls: []Line{},
+	}
+
+	if len(c.names) > 0 {
+		r.Player = top(c.names)
+	}
+
+	if c.rated.Games > 0 {
+		r.AverageOpponentElo = float64(c.ratedElo) / float64(c.rated.Games)
+		r.Performance = Performance(r.AverageOpponentElo, c.rated.Points/float64(c.rated.Games))
+	}
+
+	slices.SortStableFunc(r.Openings, func(a Line, b Line) int {
+		return cmp.Compare(b.Games, a.Games)
+	})
+	r.Openings = r.Openings[:min(c.Top, len(r.Openings))]
+
+	for _, timeControl := range TimeControls {
+		if score, ok := c.timeControls[timeControl]; ok {
+			r.TimeControls = append(r.TimeControls, Line{Name: timeControl, Score: score.finish()})
+		}
+	}
+
+	// unrated opponents are listed after the rated ones
+	r.BestWins = slices.Clone(c.wins)
+	slices.SortStableFunc(r.BestWins, func(a GameRef, b GameRef) int {
+		return cmp.Compare(b.OpponentElo, a.OpponentElo)
+	})
+	r.BestWins = r.BestWins[:min(c.Top, len(r.BestWins))]
+
+	r.WorstLosses = slices.Clone(c.losses)
+	slices.SortStableFunc(r.WorstLosses, func(a GameRef, b GameRef) int {
+		return cmp.Compare(lossKey(a.OpponentElo), lossKey(b.OpponentElo))
+	})
+	r.WorstLosses = r.WorstLosses[:min(c.Top, len(r.WorstLosses))]
+
+	if r.BestWins == nil {
+		r.BestWins = []GameRef{}
+	}
+	if r.WorstLosses == nil {
+		r.WorstLosses = []GameRef{}
+	}
+
+	return r
+}
+
+// side reports whether the player had white. A name equal to a player is
+// preferred over a name contained in one, games where both players match
+// are not found.
+func (c *Collector) side(game *types.Game) (bool, bool) {
+	name := strings.ToLower(strings.TrimSpace(c.Name))
+	white := strings.ToLower(game.White)
+	black := strings.ToLower(game.Black)
+
+	if (white == name) != (black == name) {
+		return white == name, true
+	}
+
+	if white == name {
+		return false, false
+	}
+
+	inWhite := strings.Contains(white, name)
+	inBlack := strings.Contains(black, name)
+
+	return inWhite, inWhite != inBlack
+}
+
+// Performance returns the rating that would be expected to score the fraction
+// against opponents of the average rating, a perfect or zero score counts as
+// 800 points above or below.
+func Performance(average float64, fraction float64) int {
+	difference := 800.0
+	if fraction < 1 && fraction > 0 {
+		difference = math.Min(800, math.Abs(400*math.Log10(fraction/(1-fraction))))
+	}
+
+	if fraction < 0.5 {
+		difference = -difference
+	}
+
+	return int(math.Round(average + difference))
+}
+
+// TimeControl names the category of a PGN TimeControl tag from its first
+// period, the time of a game is estimated as the base time plus 40 moves of
+// increment.
+func TimeControl(tag string) string {
+	tag = strings.TrimSpace(tag)
+	if tag == "" || tag == "?" || tag == "-" {
+		return Unknown
+	}
+
+	period, _, _ := strings.Cut(tag, ":")
+	period = strings.TrimPrefix(period, "*")
+
+	// moves/seconds, chess.com writes daily games as 1/86400
+	if _, seconds, found := strings.Cut(period, "/"); found {
+		period = seconds
+	}
+
+	base, increment, _ := strings.Cut(period, "+")
+
+	seconds, err := strconv.Atoi(base)
+	if err != nil {
+		return Unknown
+	}
+
+	if increment != "" {
+		inc, err := strconv.Atoi(increment)
+		if err != nil {
+			return Unknown
+		}
+		seconds += 40 * inc
+	}
+
+	switch {
+	case seconds >= 86400:
+		return Correspondence
+	case seconds < 180:
+		return Bullet
+	case seconds < 480:
+		return Blitz
+	case seconds < 1500:
+		return Rapid
+	default:
+		return Classical
+	}
+}
+
+// WriteMarkdown writes the report as a Markdown document.
+func (r *Report) WriteMarkdown(out io.Writer) error {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# %s\n\n", r.Player)
+	fmt.Fprintf(&sb, "- Games: %d\n", r.Total.Games)
+	fmt.Fprintf(&sb, "- Score: %s / %d (%.1f%%)\n", points(r.Total.Points), r.Total.Games, r.Total.Percent)
+	if r.RatedGames > 0 {
+		fmt.Fprintf(&sb, "- Performance: %d over %d rated games, average opponent %.0f\n",
+			r.Performance, r.RatedGames, r.AverageOpponentElo)
+	}
+
+	writeLines(&sb, "Results by colour", "Colour", []Line{
+		{Name: "White", Score: r.White},
+		{Name: "Black", Score: r.Black},
+	})
+	writeLines(&sb, "Openings", "Opening", r.Openings)
+	writeLines(&sb, "Years", "Year", r.Years)
+	writeLines(&sb, "Time controls", "Time control", r.TimeControls)
+	writeGames(&sb, "Best wins", r.BestWins)
+	writeGames(&sb, "Worst losses", r.WorstLosses)
+
+	_, err := io.WriteString(out, sb.String())
+	return err
+}
+
+func writeLines(sb *strings.Builder, title string, column string, lines []Line) {
+	if len(lines) == 0 {
+		return
+	}
+
+	fmt.Fprintf(sb, "\n## %s\n\n", title)
+	fmt.Fprintf(sb, "| %s | Games | Wins | Draws | Losses | Score |\n", column)
+	sb.WriteString("| --- | ---: | ---: | ---: | ---: | ---: |\n")
+
+	for _, line := range lines {
+		fmt.Fprintf(sb, "| %s | %d | %d | %d | %d | %.1f%% |\n", escape(line.Name),
+			line.Games, line.Wins, line.Draws, line.Losses, line.Percent)
+	}
+}
+
+func writeGames(sb *strings.Builder, title string, games []GameRef) {
+	if len(games) == 0 {
+		return
+	}
+
+	fmt.Fprintf(sb, "\n## %s\n\n", title)
+	sb.WriteString("| Date | Event | Colour | Opponent | Elo | Result | ECO |\n")
+	sb.WriteString("| --- | --- | --- | --- | ---: | --- | --- |\n")
+
+	for _, game := range games {
+		elo := ""
+		if game.OpponentElo > 0 {
+			elo = strconv.Itoa(game.OpponentElo)
+		}
+
+		fmt.Fprintf(sb, "| %s | %s | %s | %s | %s | %s | %s |\n", escape(game.Date), escape(game.Event),
+			game.Color, escape(game.Opponent), elo, game.Result, game.ECO)
+	}
+}
+
+func (s *Score) add(points float64) {
+	s.Games++
+	s.Points += points
+
+	switch points {
+	case 1:
+		s.Wins++
+	case 0:
+		s.Losses++
+	default:
+		s.Draws++
+	}
+}
+
+func (s Score) finish() Score {
+	if s.Games > 0 {
+		s.Percent = s.Points * 100 / float64(s.Games)
+	}
+	return s
+}
+
+func addTo(scores map[string]*Score, name string, points float64) {
+	score, ok := scores[name]
+	if !ok {
+		score = &Score{}
+		scores[name] = score
+	}
+	score.add(points)
+}
+
+// lines returns the scores ordered by name.
+func lines(scores map[string]*Score) []Line {
+	result := []Line{}
+	for _, name := range slices.Sorted(maps.Keys(scores)) {
+		result = append(result, Line{Name: name, Score: scores[name].finish()})
+	}
+	return result
+}
+
+// opening names an opening by its ECO code and first moves.
+func opening(eco string, movetext string) string {
+	moves := parser.ParseMoves(movetext)
+
+	var sb strings.Builder
+	if eco == "" {
+		eco = "?"
+	}
+	sb.WriteString(eco)
+
+	for i, move := range moves[:min(OpeningPlies, len(moves))] {
+		if i%2 == 0 {
+			fmt.Fprintf(&sb, " %d.%s", i/2+1, move)
+		} else {
+			fmt.Fprintf(&sb, " %s", move)
+		}
+	}
+
+	return sb.String()
+}
+
+func whitePoints(result string) float64 {
+	switch result {
+	case "1-0":
+		return 1
+	case "0-1":
+		return 0
+	default:
+		return 0.5
+	}
+}
+
+// lossKey orders losses by rating with unrated opponents last.
+func lossKey(elo int) int {
+	if elo <= 0 {
+		return math.MaxInt
+	}
+	return elo
+}
+
+// top returns the most frequent name, ties are ordered by name.
+func top(counts map[string]int) string {
+	best := ""
+	for _, name := range slices.Sorted(maps.Keys(counts)) {
+		if best == "" || counts[name] > counts[best] {
+			best = name
+		}
+	}
+	return best
+}
+
+func points(p float64) string {
+	return strconv.FormatFloat(p, 'f', -1, 64)
+}
+
+func escape(cell string) string {
+	return strings.ReplaceAll(cell, "|", "\\|")
+}
@@ -0,0 +1,134 @@
+package player
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gavink97/pgn-tools/internal/types"
+)
+
+func TestCollector(t *testing.T) {
+	games := []*types.Game{
+		{Event: "Biel", Date: "2012.08.02", White: "Carlsen, Magnus", Black: "Bacrot, Etienne", Result: "1-0",
+			BlackElo: 2700, ECO: "C65", TimeControl: "40/7200:3600", Game: "1.e4 e5 2.Nf3 Nc6 3.Bb5 Nf6 1-0"},
+		{Event: "Wijk", Date: "2013.01.14", White: "Giri, Anish", Black: "Carlsen, Magnus", Result: "1-0",
+			WhiteElo: 2800, ECO: "D37", TimeControl: "180+2", Game: "1.d4 Nf6 2.c4 e6 1-0"},
+		{Event: "Wijk", Date: "2013.??.??", White: "Carlsen, Magnus", Black: "Anand, Viswanathan", Result: "1/2",
+			BlackElo: 2800, ECO: "C65", TimeControl: "600", Game: "1.e4 e5 2.Nf3 Nc6 1/2-1/2"},
+		{Event: "Online", Date: "????.??.??", White: "Carlsen Jr", Black: "Nakamura, Hikaru", Result: "0-1",
+			ECO: "A00", TimeControl: "-", Game: "1.g4 0-1"},
+		{Event: "Biel", Date: "2012.08.03", White: "Carlsen, Magnus", Black: "Carlsen Jr", Result: "1-0", Game: "1-0"},
+		{Event: "Biel", Date: "2012.08.04", White: "Carlsen, Magnus", Black: "Giri, Anish", Result: "*", Game: "*"},
+	}
+
+	collector := NewCollector("carlsen", 2)
+	for _, game := range games {
+		collector.Add(game)
+	}
+
+	result := collector.Report()
+
+	if result.Player != "Carlsen, Magnus" {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", result.Player, "Carlsen, Magnus")
+	}
+
+	total := Score{Games: 4, Wins: 1, Draws: 1, Losses: 2, Points: 1.5, Percent: 37.5}
+	if result.Total != total {
+		t.Errorf("Incorrect Result: \nresult: %+v \nexpected: %+v", result.Total, total)
+	}
+
+	white := Score{Games: 3, Wins: 1, Draws: 1, Losses: 1, Points: 1.5, Percent: 50}
+	if result.White != white {
+		t.Errorf("Incorrect Result: \nresult: %+v \nexpected: %+v", result.White, white)
+	}
+
+	if result.RatedGames != 3 || result.Performance != 2767 {
+		t.Errorf("Incorrect Result: \nresult: %v %v \nexpected: %v %v", result.RatedGames, result.Performance, 3, 2767)
+	}
+
+	openings := []string{"C65 1.e4 e5 2.Nf3 Nc6", "A00 1.g4"}
+	var names []string
+	for _, line := range result.Openings {
+		names = append(names, line.Name)
+	}
+	if !reflect.DeepEqual(names, openings) {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", names, openings)
+	}
+
+	timeControls := []Line{
+		{Name: Blitz, Score: Score{Games: 1, Losses: 1}},
+		{Name: Rapid, Score: Score{Games: 1, Draws: 1, Points: 0.5, Percent: 50}},
+		{Name: Classical, Score: Score{Games: 1, Wins: 1, Points: 1, Percent: 100}},
+		{Name: Unknown, Score: Score{Games: 1, Losses: 1}},
+	}
+	if !reflect.DeepEqual(result.TimeControls, timeControls) {
+		t.Errorf("Incorrect Result: \nresult: %+v \nexpected: %+v", result.TimeControls, timeControls)
+	}
+
+	years := []string{"2012", "2013", Unknown}
+	names = nil
+	for _, line := range result.Years {
+		names = append(names, line.Name)
+	}
+	if !reflect.DeepEqual(names, years) {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", names, years)
+	}
+
+	if len(result.WorstLosses) != 2 || result.WorstLosses[0].Opponent != "Giri, Anish" {
+		t.Errorf("Incorrect Result: \nresult: %+v \nexpected: %v", result.WorstLosses, "Giri, Anish first")
+	}
+
+	var sb strings.Builder
+	err := result.WriteMarkdown(&sb)
+	if err != nil {
+		t.Fatalf("An error occured writing the report: %v", err)
+	}
+
+	if !strings.Contains(sb.String(), "| White | 3 | 1 | 1 | 1 | 50.0% |") {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", sb.String(), "| White | 3 | 1 | 1 | 1 | 50.0% |")
+	}
+}
+
+func TestTimeControl(t *testing.T) {
+	tests := map[string]string{
+		"60":           Bullet,
+		"120+1":        Bullet,
+		"300+0":        Blitz,
+		"900+10":       Rapid,
+		"5400+30":      Classical,
+		"40/7200:3600": Classical,
+		"*180":         Blitz,
+		"1/86400":      Correspondence,
+		"?":            Unknown,
+		"-":            Unknown,
+		"blitz":        Unknown,
+	}
+
+	for tag, expected := range tests {
+		result := TimeControl(tag)
+		if result != expected {
+			t.Errorf("Incorrect Result for %s: \nresult: %v \nexpected: %v", tag, result, expected)
+		}
+	}
+}
+
+func TestPerformance(t *testing.T) {
+	tests := []struct {
+		average  float64
+		fraction float64
+		expected int
+	}{
+		{2500, 0.5, 2500},
+		{2500, 1, 3300},
+		{2500, 0, 1700},
+		{2500, 0.75, 2691},
+	}
+
+	for _, test := range tests {
+		result := Performance(test.average, test.fraction)
+		if result != test.expected {
+			t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", result, test.expected)
+		}
+	}
+}
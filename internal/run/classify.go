@@ -0,0 +1,55 @@
+package run
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gavink97/pgn-tools/internal/global"
+	"github.com/gavink97/pgn-tools/internal/parser"
+	"github.com/gavink97/pgn-tools/internal/writer"
+)
+
+// pgn-tools classify [INPUT_PATH] [--overwrite]
+
+func Classify(args []string) {
+	start := time.Now()
+	defer func() {
+		global.Logger.Info(fmt.Sprintf("classify took: %v\n", time.Since(start)))
+	}()
+
+	input := args[1]
+	overwrite := false
+
+	for _, arg := range args[2:] {
+		if strings.EqualFold(arg, "--overwrite") {
+			overwrite = true
+		}
+	}
+
+	output := parser.OutputPath(input, "classified")
+	global.Logger.Debug(fmt.Sprintf("Writing classified pgn to: %s", output))
+
+	games, err := parser.ParsePGN(input)
+	if err != nil {
+		global.Logger.Error(fmt.Sprintf("Fatal Error: %v", err))
+		os.Exit(1)
+	}
+
+	classified := 0
+
+	for _, game := range games {
+		if parser.ClassifyGame(game, overwrite) {
+			classified++
+		}
+	}
+
+	err = writer.WriteGames(output, games)
+	if err != nil {
+		global.Logger.Error(fmt.Sprintf("Fatal Error: %v", err))
+		os.Exit(1)
+	}
+
+	global.Logger.Info(fmt.Sprintf("Classified %d games out of %d", classified, len(games)))
+}
@@ -26,7 +26,7 @@ func Convert(args []string) {
 	input := args[1]
 
 	// check for output for assign from --output
-	output := args[2]
+	output := parser.CompressPath(args[2])
 
 	if !chessbase.VerifyChessbaseInput(input) {
 		global.Logger.Error(fmt.Sprintf("Invalid input: %s", input))
@@ -116,6 +116,12 @@ func Convert(args []string) {
 
 	global.Logger.Info(fmt.Sprintf("converting %d chess games", nrRecords))
 
+	pgnWriter, err := writer.NewWriter(output, outputFormat(output))
+	if err != nil {
+		global.Logger.Error(fmt.Sprintf("Unable to create output: %v", err))
+		os.Exit(1)
+	}
+
 	for i := range nrRecords {
 		cbhRecord := cbh[46*i : 46*(i+1)]
 
@@ -134,10 +140,21 @@ func Convert(args []string) {
 			continue
 		}
 
-		writer.WritePGN(output, game)
+		err = pgnWriter.Write(game)
+		if err != nil {
+			global.Logger.Error(fmt.Sprintf("Error writing game: %v", err))
+			_ = pgnWriter.Abort()
+			os.Exit(1)
+		}
 		games++
 	}
 
+	err = pgnWriter.Close()
+	if err != nil {
+		global.Logger.Error(fmt.Sprintf("Error writing %s: %v", output, err))
+		os.Exit(1)
+	}
+
 	if errorMsgs > 0 {
 		global.Logger.Info(fmt.Sprintf("%d errors occured", errorMsgs))
 	}
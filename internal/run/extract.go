@@ -0,0 +1,108 @@
+package run
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gavink97/pgn-tools/internal/global"
+	"github.com/gavink97/pgn-tools/internal/parser"
+	"github.com/gavink97/pgn-tools/internal/writer"
+)
+
+// pgn-tools extract [INPUT_PATH] [--ply N | --after-move N | --every-ply] [--fen] [--opcodes LIST]
+
+func Extract(args []string) {
+	start := time.Now()
+	defer func() {
+		global.Logger.Info(fmt.Sprintf("extract took: %v\n", time.Since(start)))
+	}()
+
+	input := args[1]
+	format := &writer.EPDFormat{
+		Source:  input,
+		Mode:    writer.FinalPosition,
+		Opcodes: writer.EPDOpcodes,
+	}
+
+	number := func(i int) int {
+		if i+1 >= len(args) {
+			global.Logger.Error(fmt.Sprintf("Missing value for %s", args[i]))
+			os.Exit(1)
+		}
+
+		n, err := strconv.Atoi(args[i+1])
+		if err != nil || n < 0 {
+			global.Logger.Error(fmt.Sprintf("Invalid value for %s: %s", args[i], args[i+1]))
+			os.Exit(1)
+		}
+
+		return n
+	}
+
+	for i, arg := range args {
+		switch strings.ToLower(arg) {
+		case "--ply":
+			format.Mode = writer.PlyPosition
+			format.N = number(i)
+		case "--after-move":
+			format.Mode = writer.AfterMovePositions
+			format.N = number(i)
+		case "--every-ply":
+			format.Mode = writer.EveryPosition
+		case "--fen":
+			format.FEN = true
+		case "--opcodes":
+			if i+1 >= len(args) {
+				global.Logger.Error("Missing value for --opcodes")
+				os.Exit(1)
+			}
+
+			opcodes, err := writer.ParseOpcodes(args[i+1])
+			if err != nil {
+				global.Logger.Error(err.Error())
+				os.Exit(1)
+			}
+			format.Opcodes = opcodes
+		}
+	}
+
+	games, err := parser.ParsePGN(input)
+	if err != nil {
+		global.Logger.Error(fmt.Sprintf("Fatal Error: %v", err))
+		os.Exit(1)
+	}
+
+	var sink writer.Sink
+	if global.Stdout {
+		sink = writer.NewStreamSink(os.Stdout, format)
+	} else {
+		output := parser.OutputPath(input, "positions")
+		if global.Output == "" {
+			output = writer.FormatPath(output, format)
+		}
+
+		global.Logger.Debug(fmt.Sprintf("Writing positions to: %s", output))
+		sink = writer.NewFileSink(output, format)
+	}
+
+	for _, game := range games {
+		err = sink.Write(game)
+		if err != nil {
+			global.Logger.Error(fmt.Sprintf("Error writing positions: %v", err))
+			abortSinks(sink)
+			os.Exit(1)
+		}
+	}
+
+	err = sink.Close()
+	if err != nil {
+		global.Logger.Error(fmt.Sprintf("Error writing positions: %v", err))
+		abortSinks(sink)
+		os.Exit(1)
+	}
+
+	global.Logger.Info(fmt.Sprintf("Extracted positions from %d games", len(games)))
+}
@@ -0,0 +1,79 @@
+package run
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gavink97/pgn-tools/internal/fix"
+	"github.com/gavink97/pgn-tools/internal/global"
+	"github.com/gavink97/pgn-tools/internal/parser"
+	"github.com/gavink97/pgn-tools/internal/writer"
+)
+
+// pgn-tools fix [INPUT_PATH] [--change-log PATH]
+
+func Fix(args []string) {
+	start := time.Now()
+	defer func() {
+		global.Logger.Info(fmt.Sprintf("fix took: %v\n", time.Since(start)))
+	}()
+
+	input := args[1]
+	changeLog := ""
+
+	for i, arg := range args[2:] {
+		if strings.EqualFold(arg, "--change-log") && i+3 < len(args) {
+			changeLog = args[i+3]
+		}
+	}
+
+	output := parser.OutputPath(input, "fixed")
+	global.Logger.Debug(fmt.Sprintf("Writing fixed pgn to: %s", output))
+
+	// the change log is named after the output unless given
+	if changeLog == "" {
+		base := parser.TrimCompression(output)
+		changeLog = strings.TrimSuffix(base, filepath.Ext(base)) + ".log"
+	}
+
+	games, err := parser.ParsePGN(input)
+	if err != nil {
+		global.Logger.Error(fmt.Sprintf("Fatal Error: %v", err))
+		os.Exit(1)
+	}
+
+	var log strings.Builder
+	fixed := 0
+
+	for i, game := range games {
+		changes := fix.Game(game)
+		if len(changes) == 0 {
+			continue
+		}
+
+		fixed++
+		fmt.Fprintf(&log, "game %d (%s - %s):\n", i+1, game.White, game.Black)
+		for _, change := range changes {
+			fmt.Fprintf(&log, "\t%s\n", change)
+		}
+	}
+
+	err = writer.WriteGames(output, games)
+	if err != nil {
+		global.Logger.Error(fmt.Sprintf("Fatal Error: %v", err))
+		os.Exit(1)
+	}
+
+	err = os.WriteFile(changeLog, []byte(log.String()), 0600)
+	if err != nil {
+		global.Logger.Warn(fmt.Sprintf("Unable to write change log: %s", changeLog))
+		global.Logger.Warn(err.Error())
+	} else {
+		global.Logger.Info(fmt.Sprintf("Wrote the changes to: %s", changeLog))
+	}
+
+	global.Logger.Info(fmt.Sprintf("Fixed %d games out of %d", fixed, len(games)))
+}
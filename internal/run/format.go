@@ -0,0 +1,34 @@
+package run
+
+import (
+	"os"
+
+	"github.com/gavink97/pgn-tools/internal/global"
+	"github.com/gavink97/pgn-tools/internal/writer"
+)
+
+// outputFormat returns the format given with --format, or the format named by
+// the extension of the output.
+func outputFormat(output string) writer.Format {
+	var format writer.Format
+	var err error
+
+	options := writer.Options{
+		Columns:  global.Columns,
+		Notation: global.Notation,
+		Language: global.OutputLang,
+	}
+
+	if global.Format == "" {
+		format, err = writer.FormatOf(output, options)
+	} else {
+		format, err = writer.ParseFormat(global.Format, options)
+	}
+
+	if err != nil {
+		global.Logger.Error(err.Error())
+		os.Exit(1)
+	}
+
+	return format
+}
@@ -0,0 +1,129 @@
+package run
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gavink97/pgn-tools/internal/global"
+	"github.com/gavink97/pgn-tools/internal/parser"
+)
+
+// collectInputs expands files, directories, zip archives and glob patterns
+// into the pgn databases they contain, anything that is not a pgn database is
+// skipped. Stdin is read once however often it is given.
+func collectInputs(paths []string) []string {
+	var inputs []string
+	stdin := false
+
+	for _, path := range paths {
+		if path == parser.Stdin {
+			if !stdin {
+				inputs = append(inputs, path)
+			}
+			stdin = true
+			continue
+		}
+
+		if _, _, isMember := parser.SplitArchivePath(path); isMember {
+			if parser.VerifyPGNInput(path) {
+				inputs = append(inputs, path)
+			}
+			continue
+		}
+
+		if strings.ContainsAny(path, "*?[") {
+			matches, err := filepath.Glob(path)
+			if err != nil {
+				global.Logger.Warn(fmt.Sprintf("Invalid glob pattern: %s", path))
+				continue
+			}
+
+			if len(matches) == 0 {
+				global.Logger.Info(fmt.Sprintf("No files match: %s", path))
+			}
+
+			inputs = append(inputs, collectInputs(matches)...)
+			continue
+		}
+
+		stat, err := os.Stat(path)
+		if err != nil {
+			global.Logger.Warn(fmt.Sprintf("An error occured getting stats on: %s", path))
+			global.Logger.Warn(err.Error())
+			global.Logger.Info(fmt.Sprintf("Skipping file: %s", path))
+			continue
+		}
+
+		if stat.IsDir() {
+			inputs = append(inputs, walkDirectory(path)...)
+			continue
+		}
+
+		if parser.IsArchive(path) {
+			inputs = append(inputs, archiveInputs(path)...)
+			continue
+		}
+
+		if !parser.VerifyPGNInput(path) {
+			global.Logger.Info(fmt.Sprintf("Skipping file: %s", path))
+			continue
+		}
+
+		inputs = append(inputs, path)
+	}
+
+	return inputs
+}
+
+func walkDirectory(dir string) []string {
+	var inputs []string
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			global.Logger.Warn(fmt.Sprintf("An error occured accessing path: %s", path))
+			global.Logger.Warn(err.Error())
+			return nil
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		if parser.IsArchive(path) {
+			inputs = append(inputs, archiveInputs(path)...)
+			return nil
+		}
+
+		if !parser.VerifyPGNInput(path) {
+			global.Logger.Info(fmt.Sprintf("Skipping file: %s", path))
+			return nil
+		}
+
+		inputs = append(inputs, path)
+		return nil
+	})
+
+	if err != nil {
+		global.Logger.Warn(fmt.Sprintf("Error walking directory: %s", dir))
+		global.Logger.Warn(err.Error())
+	}
+
+	return inputs
+}
+
+func archiveInputs(archive string) []string {
+	inputs, err := parser.ArchiveInputs(archive)
+	if err != nil {
+		global.Logger.Warn(fmt.Sprintf("An error occured opening archive: %s", archive))
+		global.Logger.Warn(err.Error())
+		return nil
+	}
+
+	if len(inputs) == 0 {
+		global.Logger.Info(fmt.Sprintf("No pgn databases in: %s", archive))
+	}
+
+	return inputs
+}
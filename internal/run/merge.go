@@ -3,12 +3,14 @@ package run
 import (
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/gavink97/pgn-tools/internal/dedup"
 	"github.com/gavink97/pgn-tools/internal/global"
 	"github.com/gavink97/pgn-tools/internal/parser"
+	"github.com/gavink97/pgn-tools/internal/sorter"
+	"github.com/gavink97/pgn-tools/internal/types"
 	"github.com/gavink97/pgn-tools/internal/writer"
 )
 
@@ -19,7 +21,6 @@ func Merge(args []string) {
 	}()
 
 	output := global.Output
-	var inputs []string
 
 	for i, arg := range args[1:] {
 		if strings.EqualFold(arg, "--output") || strings.EqualFold(arg, "-o") {
@@ -31,57 +32,46 @@ func Merge(args []string) {
 				global.Logger.Error(fmt.Sprintf("Invalid output: %s", out))
 				os.Exit(1)
 			}
-
-			continue
 		}
+	}
 
-		if strings.EqualFold(arg, output) {
-			continue
+	output = parser.CompressPath(output)
+	inputs := collectInputs(parser.Positional(args))
+
+	var deduplicator *dedup.Deduplicator
+	if global.Reconcile || global.Dedup != "" {
+		var d *dedup.Deduplicator
+		var err error
+
+		if global.Reconcile {
+			d, err = dedup.NewReconciler(global.Dedup)
+		} else {
+			d, err = dedup.New(global.Dedup)
 		}
 
-		stat, err := os.Stat(arg)
 		if err != nil {
-			global.Logger.Warn(fmt.Sprintf("An error occured getting stats on: %s", arg))
-			global.Logger.Warn(err.Error())
-			global.Logger.Info(fmt.Sprintf("Skipping file: %s", arg))
-			continue
+			global.Logger.Error(err.Error())
+			os.Exit(1)
 		}
+		deduplicator = d
+	}
 
-		if stat.IsDir() {
-			err := filepath.Walk(arg, func(path string, info os.FileInfo, err error) error {
-				if err != nil {
-					global.Logger.Warn(fmt.Sprintf("An error occured accessing path: %s", path))
-					global.Logger.Warn(err.Error())
-					return nil
-				}
-
-				if info.IsDir() {
-					return nil
-				}
-
-				if !parser.VerifyPGNInput(path) {
-					global.Logger.Info(fmt.Sprintf("Skipping file: %s", arg))
-					return nil
-				}
-
-				inputs = append(inputs, path)
-				return nil
-			})
-
-			if err != nil {
-				global.Logger.Warn(fmt.Sprintf("Error walking directory: %s", arg))
-				global.Logger.Warn(err.Error())
-			}
+	normalizer, err := loadNormalizer()
+	if err != nil {
+		global.Logger.Error(fmt.Sprintf("Error loading aliases: %s", err))
+		os.Exit(1)
+	}
 
-			continue
-		}
+	var sink writer.Sink = writer.NewFileSink(output, outputFormat(output))
 
-		if !parser.VerifyPGNInput(arg) {
-			global.Logger.Info(fmt.Sprintf("Skipping file: %s", arg))
-			continue
+	if global.Sort != "" {
+		sort, err := parser.ParseSort(global.Sort)
+		if err != nil {
+			global.Logger.Error(fmt.Sprintf("Error parsing sort: %s", err))
+			os.Exit(1)
 		}
 
-		inputs = append(inputs, arg)
+		sink = sorter.NewExternalSink(sink, sorter.DefaultRunSize, sort)
 	}
 
 	for _, input := range inputs {
@@ -92,6 +82,63 @@ func Merge(args []string) {
 			continue
 		}
 
-		writer.WritePGN(output, games)
+		if normalizer != nil {
+			for _, game := range games {
+				normalizer.Game(game)
+			}
+		}
+
+		for _, game := range games {
+			if deduplicator != nil {
+				deduplicator.Add(game, input)
+				continue
+			}
+
+			writeMerged(sink, game)
+		}
+	}
+
+	if deduplicator != nil {
+		for _, game := range deduplicator.Games() {
+			writeMerged(sink, game)
+		}
+		reportDuplicates(deduplicator)
+	}
+
+	err = sink.Close()
+	if err != nil {
+		global.Logger.Error(fmt.Sprintf("Error writing %s: %v", output, err))
+		abortSinks(sink)
+		os.Exit(1)
+	}
+}
+
+func writeMerged(sink writer.Sink, game *types.Game) {
+	err := sink.Write(game)
+	if err != nil {
+		global.Logger.Error(fmt.Sprintf("Error writing game: %v", err))
+		abortSinks(sink)
+		os.Exit(1)
+	}
+}
+
+func reportDuplicates(deduplicator *dedup.Deduplicator) {
+	if deduplicator.Reconcile {
+		global.Logger.Info(fmt.Sprintf("Reconciled %d duplicate games", len(deduplicator.Dropped)))
+	} else {
+		global.Logger.Info(fmt.Sprintf("Dropped %d duplicate games", len(deduplicator.Dropped)))
+	}
+
+	report := deduplicator.Report()
+
+	if global.DedupReport == "" {
+		global.Logger.Debug(report)
+		return
+	}
+
+	err := os.WriteFile(global.DedupReport, []byte(report), 0600)
+	if err != nil {
+		global.Logger.Warn(fmt.Sprintf("Unable to write duplicate report: %s", global.DedupReport))
+		global.Logger.Warn(err.Error())
 	}
 }
@@ -0,0 +1,117 @@
+package run
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/gavink97/pgn-tools/internal/global"
+	"github.com/gavink97/pgn-tools/internal/normalize"
+	"github.com/gavink97/pgn-tools/internal/parser"
+	"github.com/gavink97/pgn-tools/internal/writer"
+)
+
+// pgn-tools normalize [INPUT_PATH]
+
+func Normalize(args []string) {
+	start := time.Now()
+	defer func() {
+		global.Logger.Info(fmt.Sprintf("normalize took: %v\n", time.Since(start)))
+	}()
+
+	input := args[1]
+
+	// the standalone command normalizes every name unless narrowed down
+	if !global.NormalizePlayers && !global.NormalizeEvents {
+		global.NormalizePlayers = true
+		global.NormalizeEvents = true
+	}
+
+	normalizer, err := loadNormalizer()
+	if err != nil {
+		global.Logger.Error(fmt.Sprintf("Error loading aliases: %s", err))
+		os.Exit(1)
+	}
+
+	output := parser.OutputPath(input, "normalized")
+	global.Logger.Debug(fmt.Sprintf("Writing normalized pgn to: %s", output))
+
+	games, err := parser.ParsePGN(input)
+	if err != nil {
+		global.Logger.Error(fmt.Sprintf("Fatal Error: %v", err))
+		os.Exit(1)
+	}
+
+	normalized := 0
+
+	for _, game := range games {
+		if normalizer.Game(game) {
+			normalized++
+		}
+	}
+
+	err = writer.WriteGames(output, games)
+	if err != nil {
+		global.Logger.Error(fmt.Sprintf("Fatal Error: %v", err))
+		os.Exit(1)
+	}
+
+	global.Logger.Info(fmt.Sprintf("Normalized %d games out of %d", normalized, len(games)))
+}
+
+// loadNormalizer returns the normalizer requested on the command line or nil
+// when names are not normalized.
+func loadNormalizer() (*normalize.Normalizer, error) {
+	normalizer := &normalize.Normalizer{
+		NormalizePlayers: global.NormalizePlayers || global.PlayerAliases != "",
+		NormalizeEvents:  global.NormalizeEvents || global.EventAliases != "",
+	}
+
+	if !normalizer.NormalizePlayers && !normalizer.NormalizeEvents {
+		return nil, nil
+	}
+
+	var err error
+
+	if normalizer.NormalizePlayers {
+		normalizer.Players, err = loadAliases("players", global.PlayerAliases)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if normalizer.NormalizeEvents {
+		normalizer.Events, err = loadAliases("events", global.EventAliases)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return normalizer, nil
+}
+
+// loadAliases loads the alias file given on the command line, or the user
+// config alias file of the kind when it exists.
+func loadAliases(kind string, fileName string) (*normalize.Aliases, error) {
+	if fileName != "" {
+		return normalize.LoadAliases(fileName)
+	}
+
+	fileName, err := normalize.AliasFile(kind)
+	if err != nil {
+		return nil, nil
+	}
+
+	aliases, err := normalize.LoadAliases(fileName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to load %s aliases: %v", kind, err)
+	}
+
+	global.Logger.Debug(fmt.Sprintf("Loaded %s aliases from %s", kind, fileName))
+	return aliases, nil
+}
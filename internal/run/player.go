@@ -0,0 +1,94 @@
+package run
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gavink97/pgn-tools/internal/global"
+	"github.com/gavink97/pgn-tools/internal/parser"
+	"github.com/gavink97/pgn-tools/internal/player"
+)
+
+// pgn-tools player NAME PATH... [--top N]
+
+func Player(args []string) {
+	start := time.Now()
+	defer func() {
+		global.Logger.Info(fmt.Sprintf("player took: %v\n", time.Since(start)))
+	}()
+
+	positional := parser.Positional(args)
+	if len(positional) < 2 {
+		global.Logger.Error("Enter a player name and input filepath")
+		os.Exit(1)
+	}
+
+	name := positional[0]
+	top := player.DefaultTop
+
+	for i, arg := range args {
+		if strings.EqualFold(arg, "--top") && i+1 < len(args) {
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n <= 0 {
+				global.Logger.Error(fmt.Sprintf("Invalid top: %s", args[i+1]))
+				os.Exit(1)
+			}
+			top = n
+		}
+	}
+
+	if global.Format != "" && global.Format != "markdown" && global.Format != "json" {
+		global.Logger.Error(fmt.Sprintf("unknown format: %s, expected markdown or json", global.Format))
+		os.Exit(1)
+	}
+
+	inputs := collectInputs(positional[1:])
+	if len(inputs) == 0 {
+		global.Logger.Error("No pgn databases found in the input paths")
+		os.Exit(1)
+	}
+
+	normalizer, err := loadNormalizer()
+	if err != nil {
+		global.Logger.Error(fmt.Sprintf("Error loading aliases: %s", err))
+		os.Exit(1)
+	}
+
+	queries := normalizeQueries([]*parser.Query{{
+		Conditions: []parser.QueryCondition{{Key: "player", Op: "=", Value: name}},
+	}}, normalizer)
+
+	// the name is matched against the games as the aliases resolve it
+	collector := player.NewCollector(queries[0].Conditions[0].Value, top)
+	results := make(chan queryMatch)
+	matchInputs(inputs, queries, normalizer, results)
+
+	for result := range results {
+		collector.Add(result.game)
+	}
+
+	report := collector.Report()
+	if report.Total.Games == 0 {
+		global.Logger.Warn(fmt.Sprintf("No finished games found for player: %s", name))
+	}
+
+	if global.Format == "json" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			global.Logger.Error(fmt.Sprintf("Error writing player report: %v", err))
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+		return
+	}
+
+	err = report.WriteMarkdown(os.Stdout)
+	if err != nil {
+		global.Logger.Error(fmt.Sprintf("Error writing player report: %v", err))
+		os.Exit(1)
+	}
+}
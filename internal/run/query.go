@@ -3,51 +3,313 @@ package run
 import (
 	"fmt"
 	"os"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gavink97/pgn-tools/internal/global"
+	"github.com/gavink97/pgn-tools/internal/normalize"
 	"github.com/gavink97/pgn-tools/internal/parser"
+	"github.com/gavink97/pgn-tools/internal/sorter"
+	"github.com/gavink97/pgn-tools/internal/types"
 	"github.com/gavink97/pgn-tools/internal/writer"
 )
 
+type queryMatch struct {
+	query int
+	game  *types.Game
+}
+
+// pgn-tools query [PATH...] [QUERY]
+
 func Query(args []string) {
 	start := time.Now()
 	defer func() {
 		global.Logger.Info(fmt.Sprintf("query took: %v\n", time.Since(start)))
 	}()
 
-	input := args[1]
-	keys := args[2]
+	var paths []string
+	var keys string
+
+	for _, arg := range parser.Positional(args) {
+		if isQueryArg(arg) {
+			keys = arg
+			continue
+		}
+		paths = append(paths, arg)
+	}
+
+	inputs := collectInputs(paths)
+	if len(inputs) == 0 {
+		global.Logger.Error("No pgn databases found in the input paths")
+		os.Exit(1)
+	}
 
-	query, err := parser.ParseQuery(keys)
+	queries, err := loadQueries(keys)
 	if err != nil {
 		global.Logger.Error(fmt.Sprintf("Error parsing query: %s", err))
 		os.Exit(1)
 	}
 
-	output := query.WriteTo(input)
-	global.Logger.Debug(fmt.Sprintf("Modifying pgn at: %s", output))
+	var sort *parser.Sort
+	if global.Sort != "" {
+		sort, err = parser.ParseSort(global.Sort)
+		if err != nil {
+			global.Logger.Error(fmt.Sprintf("Error parsing sort: %s", err))
+			os.Exit(1)
+		}
+	}
+
+	sinks := make([]writer.Sink, len(queries))
+	for i, query := range queries {
+		sinks[i] = querySink(query, inputs[0], len(queries) > 1)
+
+		switch {
+		case sort != nil:
+			sinks[i] = sorter.NewSortedSink(sinks[i], global.Limit, sort)
+		case global.Limit > 0:
+			sinks[i] = sorter.NewLimitSink(sinks[i], global.Limit)
+		}
+	}
 
-	games, err := parser.ParsePGN(input)
+	normalizer, err := loadNormalizer()
 	if err != nil {
-		global.Logger.Error(fmt.Sprintf("Fatal Error: %v", err))
+		global.Logger.Error(fmt.Sprintf("Error loading aliases: %s", err))
 		os.Exit(1)
 	}
 
-	matches := 0
+	matches := make([]int, len(queries))
+	results := make(chan queryMatch)
+	total := matchInputs(inputs, normalizeQueries(queries, normalizer), normalizer, results)
 
-	for _, game := range games {
-		match, err := query.Match(game)
+	for result := range results {
+		err := sinks[result.query].Write(result.game)
 		if err != nil {
-			global.Logger.Warn(fmt.Sprintf("Error evaluation game: %v", err))
-			continue
+			global.Logger.Error(fmt.Sprintf("Error writing game: %v", err))
+			abortSinks(sinks...)
+			os.Exit(1)
+		}
+		matches[result.query]++
+	}
+
+	for i, query := range queries {
+		err = sinks[i].Close()
+		if err != nil {
+			global.Logger.Error(fmt.Sprintf("Error closing output: %v", err))
+			abortSinks(sinks[i:]...)
+			os.Exit(1)
 		}
 
-		if match {
-			writer.WritePGN(output, game)
-			matches++
+		if query.Name != "" {
+			global.Logger.Info(fmt.Sprintf("%s: Matched %d games out of %d", query.Name, matches[i], *total))
+		} else {
+			global.Logger.Info(fmt.Sprintf("Matched %d games out of %d", matches[i], *total))
 		}
 	}
+}
+
+// matchInputs parses and matches the inputs concurrently and sends every
+// match to results, results is closed once all inputs are done. The matches
+// of each input are collected and sent in input order so the output is the
+// same on every run. The returned game count is complete once results is
+// closed.
+func matchInputs(inputs []string, queries []*parser.Query, normalizer *normalize.Normalizer,
+	results chan<- queryMatch) *int {
+	total := 0
+	jobs := make(chan int)
+	done := make([]chan []queryMatch, len(inputs))
+	for i := range done {
+		done[i] = make(chan []queryMatch, 1)
+	}
+
+	var mu sync.Mutex
+
+	for range min(runtime.NumCPU(), len(inputs)) {
+		go func() {
+			for index := range jobs {
+				input := inputs[index]
+				var matches []queryMatch
+
+				games, err := parser.ParsePGN(input)
+				if err != nil {
+					global.Logger.Warn(fmt.Sprintf("An error occured querying %s", input))
+					global.Logger.Warn(err.Error())
+					done[index] <- nil
+					continue
+				}
+
+				mu.Lock()
+				total += len(games)
+				mu.Unlock()
+
+				for _, game := range games {
+					if normalizer != nil {
+						normalizer.Game(game)
+					}
+
+					for i, query := range queries {
+						match, err := query.Match(game)
+						if err != nil {
+							global.Logger.Warn(fmt.Sprintf("Error evaluation game: %v", err))
+							continue
+						}
+
+						if match {
+							matches = append(matches, queryMatch{query: i, game: game})
+						}
+					}
+				}
+
+				done[index] <- matches
+			}
+		}()
+	}
+
+	go func() {
+		for i := range inputs {
+			jobs <- i
+		}
+		close(jobs)
+	}()
+
+	go func() {
+		for i := range inputs {
+			for _, match := range <-done[i] {
+				results <- match
+			}
+		}
+		close(results)
+	}()
+
+	return &total
+}
+
+// isQueryArg reports whether a positional argument is the query rather than
+// an input path.
+func isQueryArg(arg string) bool {
+	if !strings.ContainsAny(arg, "=<>") {
+		return false
+	}
+
+	_, err := os.Stat(arg)
+	return err != nil
+}
 
-	global.Logger.Info(fmt.Sprintf("Matched %d games out of %d", matches, len(games)))
+// loadQueries collects the inline query, query files and presets. When an
+// inline query is combined with named queries its conditions are added to
+// every named query.
+func loadQueries(keys string) ([]*parser.Query, error) {
+	var inline *parser.Query
+
+	if keys != "" {
+		query, err := parser.ParseQuery(keys)
+		if err != nil {
+			return nil, err
+		}
+		inline = query
+	}
+
+	var queries []*parser.Query
+
+	for _, file := range global.QueryFiles {
+		fileQueries, err := parser.ParseQueryFile(file)
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, fileQueries...)
+	}
+
+	for _, name := range global.Presets {
+		preset, err := parser.LoadPreset(name)
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, preset)
+	}
+
+	if len(queries) == 0 {
+		if inline == nil {
+			return nil, fmt.Errorf("no query given, use \"key=value\", --query-file or --preset")
+		}
+		return []*parser.Query{inline}, nil
+	}
+
+	if inline != nil {
+		for _, query := range queries {
+			query.Conditions = append(query.Conditions, inline.Conditions...)
+		}
+	}
+
+	return queries, nil
+}
+
+// normalizeQueries returns copies of the queries with the player, event and
+// site names normalized like the games they are matched against, the
+// originals keep naming the output.
+func normalizeQueries(queries []*parser.Query, normalizer *normalize.Normalizer) []*parser.Query {
+	if normalizer == nil {
+		return queries
+	}
+
+	normalized := make([]*parser.Query, len(queries))
+
+	for i, query := range queries {
+		normalized[i] = &parser.Query{Name: query.Name, Conditions: slices.Clone(query.Conditions)}
+
+		for j, condition := range normalized[i].Conditions {
+			switch {
+			case normalizer.NormalizePlayers && slices.Contains([]string{"player", "white", "black"}, condition.Key):
+				normalized[i].Conditions[j].Value = normalizer.Player(condition.Value)
+			case normalizer.NormalizeEvents && (condition.Key == "event" || condition.Key == "site"):
+				normalized[i].Conditions[j].Value = normalizer.Event(condition.Value)
+			}
+		}
+	}
+
+	return normalized
+}
+
+// abortSinks discards the outputs of the sinks before exiting on an error.
+func abortSinks(sinks ...writer.Sink) {
+	for _, sink := range sinks {
+		err := sink.Abort()
+		if err != nil {
+			global.Logger.Warn(fmt.Sprintf("Unable to remove temporary output: %v", err))
+		}
+	}
+}
+
+func querySink(query *parser.Query, input string, multiple bool) writer.Sink {
+	switch {
+	case global.CountOnly:
+		label := ""
+		if multiple {
+			label = query.Name
+		}
+		return writer.NewCountSink(os.Stdout, label)
+	case global.Stdout:
+		return writer.NewStreamSink(os.Stdout, outputFormat(""))
+	}
+
+	output := query.WriteTo(input)
+	if multiple && global.Output != "" {
+		output = writer.SplitPath(output, query.Name)
+	}
+
+	format := outputFormat(output)
+	if global.Format != "" {
+		output = writer.FormatPath(output, format)
+	}
+
+	if global.SplitBy != "" {
+		global.Logger.Debug(fmt.Sprintf("Splitting pgn by %s at: %s", global.SplitBy, output))
+		return writer.NewSplitSink(output, format, func(game *types.Game) ([]string, error) {
+			return parser.GameValues(game, global.SplitBy)
+		})
+	}
+
+	global.Logger.Debug(fmt.Sprintf("Modifying pgn at: %s", output))
+	return writer.NewFileSink(output, format)
 }
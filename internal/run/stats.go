@@ -0,0 +1,115 @@
+package run
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gavink97/pgn-tools/internal/global"
+	"github.com/gavink97/pgn-tools/internal/parser"
+	"github.com/gavink97/pgn-tools/internal/stats"
+)
+
+// pgn-tools stats [PATH...] [QUERY] [--top N]
+
+func Stats(args []string) {
+	start := time.Now()
+	defer func() {
+		global.Logger.Info(fmt.Sprintf("stats took: %v\n", time.Since(start)))
+	}()
+
+	var paths []string
+	var keys string
+
+	for _, arg := range parser.Positional(args) {
+		if isQueryArg(arg) {
+			keys = arg
+			continue
+		}
+		paths = append(paths, arg)
+	}
+
+	top := stats.DefaultTop
+
+	for i, arg := range args {
+		if strings.EqualFold(arg, "--top") && i+1 < len(args) {
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n <= 0 {
+				global.Logger.Error(fmt.Sprintf("Invalid top: %s", args[i+1]))
+				os.Exit(1)
+			}
+			top = n
+		}
+	}
+
+	if global.Format != "" && global.Format != "text" && global.Format != "json" {
+		global.Logger.Error(fmt.Sprintf("unknown format: %s, expected text or json", global.Format))
+		os.Exit(1)
+	}
+
+	inputs := collectInputs(paths)
+	if len(inputs) == 0 {
+		global.Logger.Error("No pgn databases found in the input paths")
+		os.Exit(1)
+	}
+
+	query, err := loadFilter(keys)
+	if err != nil {
+		global.Logger.Error(fmt.Sprintf("Error parsing query: %s", err))
+		os.Exit(1)
+	}
+
+	normalizer, err := loadNormalizer()
+	if err != nil {
+		global.Logger.Error(fmt.Sprintf("Error loading aliases: %s", err))
+		os.Exit(1)
+	}
+
+	collector := stats.NewCollector(top)
+	results := make(chan queryMatch)
+	matchInputs(inputs, normalizeQueries([]*parser.Query{query}, normalizer), normalizer, results)
+
+	for result := range results {
+		collector.Add(result.game)
+	}
+
+	summary := collector.Stats()
+
+	if global.Format == "json" {
+		data, err := json.MarshalIndent(summary, "", "  ")
+		if err != nil {
+			global.Logger.Error(fmt.Sprintf("Error writing stats: %v", err))
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+		return
+	}
+
+	err = summary.WriteText(os.Stdout)
+	if err != nil {
+		global.Logger.Error(fmt.Sprintf("Error writing stats: %v", err))
+		os.Exit(1)
+	}
+}
+
+// loadFilter returns the single query games are filtered by, every game
+// matches when none is given.
+func loadFilter(keys string) (*parser.Query, error) {
+	if keys == "" && len(global.QueryFiles) == 0 && len(global.Presets) == 0 {
+		return &parser.Query{}, nil
+	}
+
+	queries, err := loadQueries(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(queries) > 1 {
+		return nil, fmt.Errorf("%d queries given, filter by a single query", len(queries))
+	}
+
+	return queries[0], nil
+}
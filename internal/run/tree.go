@@ -0,0 +1,112 @@
+package run
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gavink97/pgn-tools/internal/global"
+	"github.com/gavink97/pgn-tools/internal/parser"
+	"github.com/gavink97/pgn-tools/internal/tree"
+)
+
+// pgn-tools tree [PATH...] [QUERY] [--fen FEN] [--moves MOVES] [--depth N]
+
+func Tree(args []string) {
+	start := time.Now()
+	defer func() {
+		global.Logger.Info(fmt.Sprintf("tree took: %v\n", time.Since(start)))
+	}()
+
+	var paths []string
+	var keys string
+
+	for _, arg := range parser.Positional(args) {
+		if isQueryArg(arg) {
+			keys = arg
+			continue
+		}
+		paths = append(paths, arg)
+	}
+
+	var fen, moves string
+	depth := tree.DefaultDepth
+
+	for i, arg := range args {
+		if i+1 >= len(args) {
+			break
+		}
+
+		switch strings.ToLower(arg) {
+		case "--fen":
+			fen = args[i+1]
+		case "--moves":
+			moves = args[i+1]
+		case "--depth":
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n <= 0 {
+				global.Logger.Error(fmt.Sprintf("Invalid depth: %s", args[i+1]))
+				os.Exit(1)
+			}
+			depth = n
+		}
+	}
+
+	if global.Format != "" && global.Format != "text" && global.Format != "json" {
+		global.Logger.Error(fmt.Sprintf("unknown format: %s, expected text or json", global.Format))
+		os.Exit(1)
+	}
+
+	position, err := tree.Start(fen, moves)
+	if err != nil {
+		global.Logger.Error(fmt.Sprintf("Invalid position: %v", err))
+		os.Exit(1)
+	}
+
+	inputs := collectInputs(paths)
+	if len(inputs) == 0 {
+		global.Logger.Error("No pgn databases found in the input paths")
+		os.Exit(1)
+	}
+
+	query, err := loadFilter(keys)
+	if err != nil {
+		global.Logger.Error(fmt.Sprintf("Error parsing query: %s", err))
+		os.Exit(1)
+	}
+
+	normalizer, err := loadNormalizer()
+	if err != nil {
+		global.Logger.Error(fmt.Sprintf("Error loading aliases: %s", err))
+		os.Exit(1)
+	}
+
+	builder := tree.NewBuilder(position, depth)
+	results := make(chan queryMatch)
+	matchInputs(inputs, normalizeQueries([]*parser.Query{query}, normalizer), normalizer, results)
+
+	for result := range results {
+		builder.Add(result.game)
+	}
+
+	openings := builder.Tree()
+
+	if global.Format == "json" {
+		data, err := json.MarshalIndent(openings, "", "  ")
+		if err != nil {
+			global.Logger.Error(fmt.Sprintf("Error writing tree: %v", err))
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+		return
+	}
+
+	err = openings.WriteText(os.Stdout)
+	if err != nil {
+		global.Logger.Error(fmt.Sprintf("Error writing tree: %v", err))
+		os.Exit(1)
+	}
+}
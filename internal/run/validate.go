@@ -0,0 +1,94 @@
+package run
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gavink97/pgn-tools/internal/global"
+	"github.com/gavink97/pgn-tools/internal/parser"
+	"github.com/gavink97/pgn-tools/internal/validate"
+)
+
+// pgn-tools validate [PATH...]
+
+func Validate(args []string) {
+	start := time.Now()
+
+	paths := parser.Positional(args)
+	inputs := collectInputs(paths)
+
+	// a named file that is not a pgn database fails validation
+	skipped := skippedInputs(paths, inputs)
+	for _, path := range skipped {
+		global.Logger.Error(fmt.Sprintf("Not a pgn database: %s", path))
+	}
+
+	if len(inputs) == 0 {
+		global.Logger.Error("No pgn databases to validate")
+		os.Exit(1)
+	}
+
+	problems := len(skipped)
+	games := 0
+
+	for _, input := range inputs {
+		found, count, err := validate.Input(input)
+		if err != nil {
+			global.Logger.Error(fmt.Sprintf("Unable to read %s: %v", input, err))
+			problems++
+			continue
+		}
+
+		for _, problem := range found {
+			fmt.Println(problem)
+		}
+
+		problems += len(found)
+		games += count
+	}
+
+	global.Logger.Info(fmt.Sprintf("Found %d problems in %d games", problems, games))
+	global.Logger.Info(fmt.Sprintf("validate took: %v\n", time.Since(start)))
+
+	// problems fail the command so validate can guard scripts
+	if problems > 0 {
+		os.Exit(1)
+	}
+}
+
+// skippedInputs returns the files and archive members named directly or by a
+// glob pattern that collectInputs skipped, files found walking a directory are
+// not named and may be skipped.
+func skippedInputs(paths []string, inputs []string) []string {
+	var skipped []string
+
+	for _, path := range paths {
+		if path == parser.Stdin || slices.Contains(inputs, path) {
+			continue
+		}
+
+		if _, _, isMember := parser.SplitArchivePath(path); isMember {
+			skipped = append(skipped, path)
+			continue
+		}
+
+		if strings.ContainsAny(path, "*?[") {
+			matches, _ := filepath.Glob(path)
+			skipped = append(skipped, skippedInputs(matches, inputs)...)
+			continue
+		}
+
+		stat, err := os.Stat(path)
+		if err == nil && (stat.IsDir() || parser.IsArchive(path)) {
+			continue
+		}
+
+		skipped = append(skipped, path)
+	}
+
+	return skipped
+}
@@ -0,0 +1,266 @@
+package sorter
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/gavink97/pgn-tools/internal/types"
+	"github.com/gavink97/pgn-tools/internal/writer"
+)
+
+// DefaultRunSize is the number of games an ExternalSink sorts in memory before
+// spilling them to disk.
+const DefaultRunSize = 50000
+
+// ExternalSink sorts any number of games with an external merge sort. Games
+// are sorted in runs of RunSize, every full run is spilled to a temporary file
+// with the sort values of its games and the runs are merged into the inner
+// sink once closed. The sort is stable.
+type ExternalSink struct {
+	Inner   writer.Sink
+	RunSize int
+	sort    Order
+	games   []record
+	dir     string
+	runs    []string
+}
+
+// record is a game with its sort values as it is written to a run.
+type record struct {
+	Game   *types.Game
+	Values []any
+}
+
+func NewExternalSink(inner writer.Sink, runSize int, sort Order) *ExternalSink {
+	if runSize <= 0 {
+		runSize = DefaultRunSize
+	}
+
+	return &ExternalSink{
+		Inner:   inner,
+		RunSize: runSize,
+		sort:    sort,
+	}
+}
+
+func (s *ExternalSink) Write(game *types.Game) error {
+	s.games = append(s.games, record{Game: game, Values: s.sort.Values(game)})
+
+	if len(s.games) < s.RunSize {
+		return nil
+	}
+
+	return s.spill()
+}
+
+func (s *ExternalSink) Close() error {
+	defer s.cleanup()
+
+	slices.SortStableFunc(s.games, s.compare)
+
+	// everything fit in a single run so there is nothing to merge
+	if len(s.runs) == 0 {
+		for _, r := range s.games {
+			err := s.Inner.Write(r.Game)
+			if err != nil {
+				return errors.Join(err, s.Inner.Abort())
+			}
+		}
+
+		return s.Inner.Close()
+	}
+
+	if len(s.games) > 0 {
+		err := s.spill()
+		if err != nil {
+			return errors.Join(err, s.Inner.Abort())
+		}
+	}
+
+	err := s.merge()
+	if err != nil {
+		return errors.Join(err, s.Inner.Abort())
+	}
+
+	return s.Inner.Close()
+}
+
+// Abort removes the spilled runs and discards the output of the inner sink.
+func (s *ExternalSink) Abort() error {
+	s.cleanup()
+	s.games = nil
+	return s.Inner.Abort()
+}
+
+// spill sorts the buffered games and writes them to a new run file, the
+// temporary directory is removed when a run can not be written.
+func (s *ExternalSink) spill() (err error) {
+	defer func() {
+		if err != nil {
+			s.cleanup()
+		}
+	}()
+
+	if s.dir == "" {
+		dir, err := os.MkdirTemp("", "pgn-tools-sort-*")
+		if err != nil {
+			return err
+		}
+		s.dir = dir
+	}
+
+	slices.SortStableFunc(s.games, s.compare)
+
+	path := filepath.Join(s.dir, fmt.Sprintf("run-%d", len(s.runs)))
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	encoder := gob.NewEncoder(w)
+
+	for _, r := range s.games {
+		err = encoder.Encode(r)
+		if err != nil {
+			return errors.Join(err, f.Close())
+		}
+	}
+
+	err = w.Flush()
+	if err != nil {
+		return errors.Join(err, f.Close())
+	}
+
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	s.runs = append(s.runs, path)
+	s.games = s.games[:0]
+	return nil
+}
+
+func (s *ExternalSink) compare(a record, b record) int {
+	return s.sort.CompareValues(a.Values, b.Values)
+}
+
+type run struct {
+	file    *os.File
+	decoder *gob.Decoder
+	record  *record
+	index   int
+}
+
+func (r *run) next() error {
+	decoded := &record{}
+
+	err := r.decoder.Decode(decoded)
+	if errors.Is(err, io.EOF) {
+		r.record = nil
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	r.record = decoded
+	return nil
+}
+
+// runHeap keeps the run with the first game in sort order on top, ties go to
+// the earlier run to keep the sort stable.
+type runHeap struct {
+	runs []*run
+	sort Order
+}
+
+func (h *runHeap) Len() int { return len(h.runs) }
+func (h *runHeap) Less(i, j int) bool {
+	cmp := h.sort.CompareValues(h.runs[i].record.Values, h.runs[j].record.Values)
+	if cmp != 0 {
+		return cmp < 0
+	}
+	return h.runs[i].index < h.runs[j].index
+}
+func (h *runHeap) Swap(i, j int) { h.runs[i], h.runs[j] = h.runs[j], h.runs[i] }
+func (h *runHeap) Push(x any)    { h.runs = append(h.runs, x.(*run)) }
+func (h *runHeap) Pop() any {
+	last := h.runs[len(h.runs)-1]
+	h.runs = h.runs[:len(h.runs)-1]
+	return last
+}
+
+func (s *ExternalSink) merge() error {
+	h := &runHeap{sort: s.sort}
+	var files []*os.File
+
+	defer func() {
+		for _, f := range files {
+			_ = f.Close()
+		}
+	}()
+
+	for i, path := range s.runs {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		files = append(files, f)
+
+		r := &run{file: f, decoder: gob.NewDecoder(bufio.NewReader(f)), index: i}
+
+		err = r.next()
+		if err != nil {
+			return fmt.Errorf("unable to read sort run %s: %v", path, err)
+		}
+
+		if r.record != nil {
+			h.runs = append(h.runs, r)
+		}
+	}
+
+	heap.Init(h)
+
+	for h.Len() > 0 {
+		r := h.runs[0]
+
+		err := s.Inner.Write(r.record.Game)
+		if err != nil {
+			return err
+		}
+
+		err = r.next()
+		if err != nil {
+			return fmt.Errorf("unable to read sort run %s: %v", r.file.Name(), err)
+		}
+
+		if r.record == nil {
+			heap.Pop(h)
+			continue
+		}
+
+		heap.Fix(h, 0)
+	}
+
+	return nil
+}
+
+func (s *ExternalSink) cleanup() {
+	if s.dir == "" {
+		return
+	}
+
+	_ = os.RemoveAll(s.dir)
+	s.dir = ""
+	s.runs = nil
+}
@@ -0,0 +1,108 @@
+package sorter
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/gavink97/pgn-tools/internal/parser"
+	"github.com/gavink97/pgn-tools/internal/types"
+)
+
+type collectSink struct {
+	games   []*types.Game
+	closed  bool
+	aborted bool
+}
+
+func (s *collectSink) Write(game *types.Game) error {
+	s.games = append(s.games, game)
+	return nil
+}
+
+func (s *collectSink) Close() error {
+	s.closed = true
+	return nil
+}
+
+func (s *collectSink) Abort() error {
+	s.aborted = true
+	return nil
+}
+
+func TestExternalSink(t *testing.T) {
+	sort, err := parser.ParseSort("whiteelo")
+	if err != nil {
+		t.Fatalf("An error occured parsing sort: %v", err)
+	}
+
+	for _, runSize := range []int{2, 3, 100} {
+		collected := &collectSink{}
+		sink := NewExternalSink(collected, runSize, sort)
+
+		for _, game := range []*types.Game{
+			{White: "a", WhiteElo: 2400},
+			{White: "b", WhiteElo: 2700},
+			{White: "c", WhiteElo: 2100},
+			{White: "d", WhiteElo: 2800},
+			{White: "e", WhiteElo: 2700},
+			{White: "f", WhiteElo: 2000},
+			{White: "g", WhiteElo: 2400},
+		} {
+			err := sink.Write(game)
+			if err != nil {
+				t.Fatalf("An error occured writing game: %v", err)
+			}
+		}
+
+		err = sink.Close()
+		if err != nil {
+			t.Fatalf("An error occured closing sink: %v", err)
+		}
+
+		var result []string
+		for _, game := range collected.games {
+			result = append(result, game.White)
+		}
+
+		expected := []string{"f", "c", "a", "g", "b", "e", "d"}
+		if !reflect.DeepEqual(result, expected) {
+			t.Errorf("Incorrect Result for run size %d: \nresult: %v \nexpected: %v", runSize, result, expected)
+		}
+
+		if !collected.closed || sink.dir != "" {
+			t.Errorf("Incorrect Result for run size %d: sink was not cleaned up", runSize)
+		}
+	}
+}
+
+func TestExternalSinkAbort(t *testing.T) {
+	sort, err := parser.ParseSort("whiteelo")
+	if err != nil {
+		t.Fatalf("An error occured parsing sort: %v", err)
+	}
+
+	collected := &collectSink{}
+	sink := NewExternalSink(collected, 2, sort)
+
+	for _, elo := range []int{2400, 2700, 2100} {
+		err := sink.Write(&types.Game{WhiteElo: elo})
+		if err != nil {
+			t.Fatalf("An error occured writing game: %v", err)
+		}
+	}
+
+	dir := sink.dir
+	if dir == "" {
+		t.Fatalf("Incorrect Result: no run was spilled")
+	}
+
+	err = sink.Abort()
+	if err != nil {
+		t.Fatalf("An error occured aborting sink: %v", err)
+	}
+
+	if _, err := os.Stat(dir); !os.IsNotExist(err) || !collected.aborted || collected.closed {
+		t.Errorf("Incorrect Result: sink was not cleaned up")
+	}
+}
@@ -0,0 +1,73 @@
+package sorter
+
+import (
+	"errors"
+
+	"github.com/gavink97/pgn-tools/internal/types"
+	"github.com/gavink97/pgn-tools/internal/writer"
+)
+
+// SortedSink holds the games in a bounded heap and writes them to the inner
+// sink in sort order once closed.
+type SortedSink struct {
+	Inner writer.Sink
+	topN  *TopN
+}
+
+func NewSortedSink(inner writer.Sink, limit int, sort Order) *SortedSink {
+	return &SortedSink{
+		Inner: inner,
+		topN:  NewTopN(limit, sort),
+	}
+}
+
+func (s *SortedSink) Write(game *types.Game) error {
+	s.topN.Add(game)
+	return nil
+}
+
+func (s *SortedSink) Close() error {
+	for _, game := range s.topN.Games() {
+		err := s.Inner.Write(game)
+		if err != nil {
+			return errors.Join(err, s.Inner.Abort())
+		}
+	}
+
+	return s.Inner.Close()
+}
+
+func (s *SortedSink) Abort() error {
+	return s.Inner.Abort()
+}
+
+// LimitSink passes on the first games up to the limit and drops the rest.
+type LimitSink struct {
+	Inner   writer.Sink
+	Limit   int
+	written int
+}
+
+func NewLimitSink(inner writer.Sink, limit int) *LimitSink {
+	return &LimitSink{
+		Inner: inner,
+		Limit: limit,
+	}
+}
+
+func (s *LimitSink) Write(game *types.Game) error {
+	if s.written >= s.Limit {
+		return nil
+	}
+
+	s.written++
+	return s.Inner.Write(game)
+}
+
+func (s *LimitSink) Close() error {
+	return s.Inner.Close()
+}
+
+func (s *LimitSink) Abort() error {
+	return s.Inner.Abort()
+}
@@ -0,0 +1,91 @@
+package sorter
+
+import (
+	"container/heap"
+	"slices"
+
+	"github.com/gavink97/pgn-tools/internal/types"
+)
+
+// Order orders games by sort values computed once per game, so sorting does
+// not recompute them on every comparison. *parser.Sort is an Order.
+type Order interface {
+	Values(game *types.Game) []any
+	CompareValues(a []any, b []any) int
+}
+
+type entry struct {
+	game   *types.Game
+	values []any
+	seq    int
+}
+
+// TopN keeps the first n games in sort order using a bounded heap, with a
+// limit of 0 every game is kept. Games comparing equal keep their arrival
+// order.
+type TopN struct {
+	limit   int
+	sort    Order
+	entries []entry
+	seq     int
+}
+
+func NewTopN(limit int, sort Order) *TopN {
+	return &TopN{
+		limit: limit,
+		sort:  sort,
+	}
+}
+
+func (t *TopN) order(a entry, b entry) int {
+	cmp := t.sort.CompareValues(a.values, b.values)
+	if cmp != 0 {
+		return cmp
+	}
+	return a.seq - b.seq
+}
+
+// the heap keeps the last game in sort order on top so it can be evicted
+func (t *TopN) Len() int           { return len(t.entries) }
+func (t *TopN) Less(i, j int) bool { return t.order(t.entries[i], t.entries[j]) > 0 }
+func (t *TopN) Swap(i, j int)      { t.entries[i], t.entries[j] = t.entries[j], t.entries[i] }
+
+func (t *TopN) Push(x any) {
+	t.entries = append(t.entries, x.(entry))
+}
+
+func (t *TopN) Pop() any {
+	last := t.entries[len(t.entries)-1]
+	t.entries = t.entries[:len(t.entries)-1]
+	return last
+}
+
+func (t *TopN) Add(game *types.Game) {
+	e := entry{game: game, values: t.sort.Values(game), seq: t.seq}
+	t.seq++
+
+	if t.limit > 0 && len(t.entries) == t.limit {
+		if t.order(e, t.entries[0]) >= 0 {
+			return
+		}
+
+		t.entries[0] = e
+		heap.Fix(t, 0)
+		return
+	}
+
+	heap.Push(t, e)
+}
+
+// Games returns the kept games in sort order.
+func (t *TopN) Games() []*types.Game {
+	entries := slices.Clone(t.entries)
+	slices.SortFunc(entries, t.order)
+
+	games := make([]*types.Game, len(entries))
+	for i, e := range entries {
+		games[i] = e.game
+	}
+
+	return games
+}
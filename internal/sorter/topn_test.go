@@ -0,0 +1,47 @@
+package sorter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gavink97/pgn-tools/internal/parser"
+	"github.com/gavink97/pgn-tools/internal/types"
+)
+
+func TestTopN(t *testing.T) {
+	sort, err := parser.ParseSort("whiteelo desc")
+	if err != nil {
+		t.Fatalf("An error occured parsing sort: %v", err)
+	}
+
+	samples := []struct {
+		limit    int
+		expected []string
+	}{
+		{limit: 3, expected: []string{"d", "b", "e"}},
+		{limit: 0, expected: []string{"d", "b", "e", "a", "c"}},
+	}
+
+	for _, sample := range samples {
+		topN := NewTopN(sample.limit, sort)
+
+		for _, game := range []*types.Game{
+			{White: "a", WhiteElo: 2400},
+			{White: "b", WhiteElo: 2700},
+			{White: "c", WhiteElo: 2100},
+			{White: "d", WhiteElo: 2800},
+			{White: "e", WhiteElo: 2700},
+		} {
+			topN.Add(game)
+		}
+
+		var result []string
+		for _, game := range topN.Games() {
+			result = append(result, game.White)
+		}
+
+		if !reflect.DeepEqual(result, sample.expected) {
+			t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", result, sample.expected)
+		}
+	}
+}
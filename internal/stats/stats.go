@@ -0,0 +1,212 @@
+package stats
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/gavink97/pgn-tools/internal/fix"
+	"github.com/gavink97/pgn-tools/internal/parser"
+	"github.com/gavink97/pgn-tools/internal/types"
+)
+
+// DefaultTop is the number of players, events and openings listed.
+const DefaultTop = 10
+
+var Results = []string{"1-0", "0-1", "1/2-1/2", "*"}
+
+type Count struct {
+	Name  string `json:"name"`
+	Games int    `json:"games"`
+}
+
+// Stats summarizes a set of games. Averages only count the games that have
+// the value, percentages are of all games.
+type Stats struct {
+	Games           int            `json:"games"`
+	FirstDate       string         `json:"first_date,omitempty"`
+	LastDate        string         `json:"last_date,omitempty"`
+	Results         map[string]int `json:"results"`
+	AverageWhiteElo float64        `json:"average_white_elo"`
+	AverageBlackElo float64        `json:"average_black_elo"`
+	AveragePlies    float64        `json:"average_plies"`
+	DecisivePercent float64        `json:"decisive_percent"`
+	WhiteWinPercent float64        `json:"white_win_percent"`
+	BlackWinPercent float64        `json:"black_win_percent"`
+	TopPlayers      []Count        `json:"top_players"`
+	TopEvents       []Count        `json:"top_events"`
+	TopECOs         []Count        `json:"top_ecos"`
+}
+
+// Collector gathers the statistics of games added one at a time.
+type Collector struct {
+	Top        int
+	games      int
+	firstDate  string
+	lastDate   string
+	results    map[string]int
+	whiteElo   int
+	whiteRated int
+	blackElo   int
+	blackRated int
+	plies      int
+	players    map[string]int
+	events     map[string]int
+	ecos       map[string]int
+}
+
+func NewCollector(top int) *Collector {
+	if top <= 0 {
+		top = DefaultTop
+	}
+
+	return &Collector{
+		Top:     top,
+		results: map[string]int{},
+		players: map[string]int{},
+		events:  map[string]int{},
+		ecos:    map[string]int{},
+	}
+}
+
+func (c *Collector) Add(game *types.Game) {
+	c.games++
+
+	result, valid := fix.NormalizeResult(game.Result)
+	if !valid {
+		result = "*"
+	}
+	c.results[result]++
+
+	if date := parser.DateKey(game.Date); date != "" {
+		if c.firstDate == "" || date < parser.DateKey(c.firstDate) {
+			c.firstDate = game.Date
+		}
+		if c.lastDate == "" || date > parser.DateKey(c.lastDate) {
+			c.lastDate = game.Date
+		}
+	}
+
+	if game.WhiteElo > 0 {
+		c.whiteElo += game.WhiteElo
+		c.whiteRated++
+	}
+
+	if game.BlackElo > 0 {
+		c.blackElo += game.BlackElo
+		c.blackRated++
+	}
+
+	c.plies += len(parser.ParseMoves(game.Game))
+
+	for _, player := range []string{game.White, game.Black} {
+		if known(player) {
+			c.players[player]++
+		}
+	}
+
+	if known(game.Event) {
+		c.events[game.Event]++
+	}
+
+	if known(game.ECO) {
+		c.ecos[game.ECO]++
+	}
+}
+
+func (c *Collector) Stats() *Stats {
+	s := &Stats{
+		Games:        c.games,
+		FirstDate:    c.firstDate,
+		LastDate:     c.lastDate,
+		Results:      map[string]int{},
+		TopPlayers:   top(c.players, c.Top),
+		TopEvents:    top(c.events, c.Top),
+		TopECOs:      top(c.ecos, c.Top),
+		AveragePlies: average(c.plies, c.games),
+	}
+
+	for _, result := range Results {
+		s.Results[result] = c.results[result]
+	}
+
+	s.AverageWhiteElo = average(c.whiteElo, c.whiteRated)
+	s.AverageBlackElo = average(c.blackElo, c.blackRated)
+	s.WhiteWinPercent = percent(c.results["1-0"], c.games)
+	s.BlackWinPercent = percent(c.results["0-1"], c.games)
+	s.DecisivePercent = percent(c.results["1-0"]+c.results["0-1"], c.games)
+
+	return s
+}
+
+// WriteText writes the statistics as a plain text report.
+func (s *Stats) WriteText(out io.Writer) error {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "Games:           %d\n", s.Games)
+	if s.FirstDate != "" {
+		fmt.Fprintf(&sb, "Dates:           %s - %s\n", s.FirstDate, s.LastDate)
+	}
+
+	var results []string
+	for _, result := range Results {
+		results = append(results, fmt.Sprintf("%s %d (%.1f%%)", result, s.Results[result], percent(s.Results[result], s.Games)))
+	}
+	fmt.Fprintf(&sb, "Results:         %s\n", strings.Join(results, ", "))
+
+	fmt.Fprintf(&sb, "Average Elo:     white %.0f, black %.0f\n", s.AverageWhiteElo, s.AverageBlackElo)
+	fmt.Fprintf(&sb, "Average length:  %.1f plies (%.1f moves)\n", s.AveragePlies, s.AveragePlies/2)
+	fmt.Fprintf(&sb, "Decisive:        %.1f%% (white %.1f%%, black %.1f%%)\n",
+		s.DecisivePercent, s.WhiteWinPercent, s.BlackWinPercent)
+
+	writeCounts(&sb, "Top players", s.TopPlayers)
+	writeCounts(&sb, "Top events", s.TopEvents)
+	writeCounts(&sb, "Top openings", s.TopECOs)
+
+	_, err := io.WriteString(out, sb.String())
+	return err
+}
+
+func writeCounts(sb *strings.Builder, title string, counts []Count) {
+	if len(counts) == 0 {
+		return
+	}
+
+	fmt.Fprintf(sb, "\n%s:\n", title)
+	for _, count := range counts {
+		fmt.Fprintf(sb, "\t%6d  %s\n", count.Games, count.Name)
+	}
+}
+
+// top returns the most frequent names, ties are ordered by name.
+func top(counts map[string]int, n int) []Count {
+	result := []Count{}
+	for _, name := range slices.Sorted(maps.Keys(counts)) {
+		result = append(result, Count{Name: name, Games: counts[name]})
+	}
+
+	slices.SortStableFunc(result, func(a Count, b Count) int {
+		return cmp.Compare(b.Games, a.Games)
+	})
+
+	return result[:min(n, len(result))]
+}
+
+func known(value string) bool {
+	value = strings.TrimSpace(value)
+	return value != "" && value != "?"
+}
+
+func average(sum int, count int) float64 {
+	if count == 0 {
+		return 0
+	}
+	return float64(sum) / float64(count)
+}
+
+func percent(part int, total int) float64 {
+	return average(part*100, total)
+}
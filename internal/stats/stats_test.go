@@ -0,0 +1,55 @@
+package stats
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gavink97/pgn-tools/internal/types"
+)
+
+func TestCollector(t *testing.T) {
+	games := []*types.Game{
+		{Event: "Biel", Date: "2012.08.02", White: "Carlsen", Black: "Bacrot", Result: "1-0", WhiteElo: 2837, BlackElo: 2713, ECO: "C65", Game: "1.e4 e5 2.Nf3 Nc6 1-0"},
+		{Event: "Biel", Date: "2011.??.??", White: "Bacrot", Black: "Carlsen", Result: "0-1", WhiteElo: 2700, ECO: "C65", Game: "1.e4 e5 0-1"},
+		{Event: "Wijk", Date: "????.??.??", White: "Giri", Black: "Carlsen", Result: "1/2", ECO: "B90", Game: "1.d4 1/2-1/2"},
+		{Event: "?", Date: "2013.01.14", White: "Giri", Black: "Anand", Result: "?", Game: "*"},
+	}
+
+	collector := NewCollector(2)
+	for _, game := range games {
+		collector.Add(game)
+	}
+
+	result := collector.Stats()
+
+	expected := &Stats{
+		Games:           4,
+		FirstDate:       "2011.??.??",
+		LastDate:        "2013.01.14",
+		Results:         map[string]int{"1-0": 1, "0-1": 1, "1/2-1/2": 1, "*": 1},
+		AverageWhiteElo: 2768.5,
+		AverageBlackElo: 2713,
+		AveragePlies:    7.0 / 4,
+		DecisivePercent: 50,
+		WhiteWinPercent: 25,
+		BlackWinPercent: 25,
+		TopPlayers:      []Count{{"Carlsen", 3}, {"Bacrot", 2}},
+		TopEvents:       []Count{{"Biel", 2}, {"Wijk", 1}},
+		TopECOs:         []Count{{"C65", 2}, {"B90", 1}},
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Incorrect Result: \nresult: %+v \nexpected: %+v", result, expected)
+	}
+
+	var sb strings.Builder
+	err := result.WriteText(&sb)
+	if err != nil {
+		t.Fatalf("An error occured writing stats: %v", err)
+	}
+
+	if !strings.Contains(sb.String(), "Decisive:        50.0% (white 25.0%, black 25.0%)") {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", sb.String(), "Decisive: 50.0%")
+	}
+}
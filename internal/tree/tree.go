@@ -0,0 +1,279 @@
+package tree
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/gavink97/pgn-tools/internal/chess"
+	"github.com/gavink97/pgn-tools/internal/fix"
+	"github.com/gavink97/pgn-tools/internal/parser"
+	"github.com/gavink97/pgn-tools/internal/types"
+)
+
+// DefaultDepth is the number of half moves the tree is built below the
+// position.
+const DefaultDepth = 1
+
+// Node is a move played in the position of its parent with the results of
+// the games that played it. Score is the share of the points won by the side
+// that played the move, AverageElo is the average rating of that side.
+type Node struct {
+	Move       string  `json:"move"`
+	Games      int     `json:"games"`
+	WhiteWins  int     `json:"white_wins"`
+	Draws      int     `json:"draws"`
+	BlackWins  int     `json:"black_wins"`
+	Score      float64 `json:"score"`
+	AverageElo float64 `json:"average_elo"`
+	LastPlayed string  `json:"last_played,omitempty"`
+	Moves      []*Node `json:"moves,omitempty"`
+	number     int
+	color      chess.Color
+	elo        int
+	rated      int
+	children   map[string]*Node
+}
+
+// Tree is the opening tree below a position.
+type Tree struct {
+	FEN   string  `json:"fen"`
+	Games int     `json:"games"`
+	Moves []*Node `json:"moves"`
+}
+
+// Builder gathers the tree of a position from games added one at a time. A
+// game is added from the first time it reaches the position, so transpositions
+// are found. Games without a result are skipped.
+type Builder struct {
+	Depth int
+	start *chess.Position
+	key   string
+	games int
+	root  *Node
+}
+
+func NewBuilder(start *chess.Position, depth int) *Builder {
+	if depth <= 0 {
+		depth = DefaultDepth
+	}
+
+	return &Builder{
+		Depth: depth,
+		start: start,
+		key:   start.EPD(),
+		root:  &Node{children: map[string]*Node{}},
+	}
+}
+
+// Start returns the position reached by playing the moves from the FEN, or
+// from the starting position when the FEN is empty.
+func Start(fen string, moves string) (*chess.Position, error) {
+	pos := chess.StartingPosition()
+	if fen != "" {
+		p, err := chess.ParseFEN(fen)
+		if err != nil {
+			return nil, err
+		}
+		pos = p
+	}
+
+	for _, san := range parser.ParseMoves(moves) {
+		move, err := pos.ParseSAN(san)
+		if err != nil {
+			return nil, fmt.Errorf("illegal move %s in %s: %w", san, pos.FEN(), err)
+		}
+		pos = pos.Play(move)
+	}
+
+	return pos, nil
+}
+
+func (b *Builder) Add(game *types.Game) {
+	result, valid := fix.NormalizeResult(game.Result)
+	if !valid || result == "*" {
+		return
+	}
+
+	moves, found := b.line(game)
+	if !found {
+		return
+	}
+
+	b.games++
+	node := b.root
+
+	for _, move := range moves {
+		child, ok := node.children[move.san]
+		if !ok {
+			child = &Node{Move: move.san, number: move.number, color: move.color, children: map[string]*Node{}}
+			node.children[move.san] = child
+		}
+
+		child.add(game, result)
+		node = child
+	}
+}
+
+func (b *Builder) Tree() *Tree {
+	return &Tree{
+		FEN:   b.start.FEN(),
+		Games: b.games,
+		Moves: finish(b.root.children),
+	}
+}
+
+type treeMove struct {
+	san    string
+	number int
+	color  chess.Color
+}
+
+// line replays the main line of the game to the position and returns the
+// moves played from it, the replay stops at the first illegal move.
+func (b *Builder) line(game *types.Game) ([]treeMove, bool) {
+	pos := chess.StartingPosition()
+	if game.FEN != "" {
+		p, err := chess.ParseFEN(game.FEN)
+		if err != nil {
+			return nil, false
+		}
+		pos = p
+	}
+
+	var moves []treeMove
+	found := false
+
+	for _, san := range parser.ParseMoves(game.Game) {
+		if !found && pos.EPD() == b.key {
+			found = true
+		}
+
+		if found && len(moves) == b.Depth {
+			break
+		}
+
+		move, err := pos.ParseSAN(san)
+		if err != nil {
+			break
+		}
+
+		if found {
+			moves = append(moves, treeMove{san: pos.SAN(move), number: pos.FullmoveNumber, color: pos.Turn})
+		}
+
+		pos = pos.Play(move)
+	}
+
+	// a game ending in the position is counted without a move
+	if !found && pos.EPD() == b.key {
+		found = true
+	}
+
+	return moves, found
+}
+
+func (n *Node) add(game *types.Game, result string) {
+	n.Games++
+
+	switch result {
+	case "1-0":
+		n.WhiteWins++
+	case "0-1":
+		n.BlackWins++
+	default:
+		n.Draws++
+	}
+
+	elo := game.WhiteElo
+	if n.color == chess.Black {
+		elo = game.BlackElo
+	}
+
+	if elo > 0 {
+		n.elo += elo
+		n.rated++
+	}
+
+	if date := parser.DateKey(game.Date); date != "" && date > parser.DateKey(n.LastPlayed) {
+		n.LastPlayed = game.Date
+	}
+}
+
+// finish orders the moves by the number of games, ties are ordered by move.
+func finish(children map[string]*Node) []*Node {
+	nodes := []*Node{}
+
+	for _, node := range children {
+		wins := node.WhiteWins
+		if node.color == chess.Black {
+			wins = node.BlackWins
+		}
+
+		node.Score = (float64(wins) + float64(node.Draws)/2) * 100 / float64(node.Games)
+		if node.rated > 0 {
+			node.AverageElo = float64(node.elo) / float64(node.rated)
+		}
+
+		node.Moves = nil
+		if len(node.children) > 0 {
+			node.Moves = finish(node.children)
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	slices.SortFunc(nodes, func(a *Node, b *Node) int {
+		return cmp.Or(cmp.Compare(b.Games, a.Games), cmp.Compare(a.Move, b.Move))
+	})
+
+	return nodes
+}
+
+// WriteText writes the tree as a table, the moves below a move are indented.
+func (t *Tree) WriteText(out io.Writer) error {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "Position: %s\n", t.FEN)
+	fmt.Fprintf(&sb, "Games:    %d\n\n", t.Games)
+	fmt.Fprintf(&sb, "%-20s %7s %7s %7s %7s %7s %8s  %s\n",
+		"Move", "Games", "Score", "White", "Draw", "Black", "Avg Elo", "Last played")
+
+	writeNodes(&sb, t.Moves, 0)
+
+	_, err := io.WriteString(out, sb.String())
+	return err
+}
+
+func writeNodes(sb *strings.Builder, nodes []*Node, indent int) {
+	for _, node := range nodes {
+		move := strings.Repeat("  ", indent) + node.label()
+
+		elo := "-"
+		if node.AverageElo > 0 {
+			elo = fmt.Sprintf("%.0f", node.AverageElo)
+		}
+
+		fmt.Fprintf(sb, "%-20s %7d %6.1f%% %6.1f%% %6.1f%% %6.1f%% %8s  %s\n", move, node.Games, node.Score,
+			percent(node.WhiteWins, node.Games), percent(node.Draws, node.Games),
+			percent(node.BlackWins, node.Games), elo, node.LastPlayed)
+
+		writeNodes(sb, node.Moves, indent+1)
+	}
+}
+
+func (n *Node) label() string {
+	if n.color == chess.Black {
+		return fmt.Sprintf("%d...%s", n.number, n.Move)
+	}
+	return fmt.Sprintf("%d.%s", n.number, n.Move)
+}
+
+func percent(part int, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) * 100 / float64(total)
+}
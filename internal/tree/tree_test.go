@@ -0,0 +1,77 @@
+package tree
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gavink97/pgn-tools/internal/types"
+)
+
+func TestBuilder(t *testing.T) {
+	games := []*types.Game{
+		{Date: "2020.01.01", Result: "1-0", WhiteElo: 2850, BlackElo: 2600, Game: "1.e4 c5 2.Nf3 d6 3.d4 1-0"},
+		{Date: "2021.05.01", Result: "1/2-1/2", WhiteElo: 2700, BlackElo: 2650, Game: "1.Nf3 c5 2.e4 d6 1/2-1/2"},
+		{Date: "2019.??.??", Result: "0-1", BlackElo: 2500, Game: "1.e4 c5 2.Nf3 Nc6 0-1"},
+		{Date: "2022.01.01", Result: "*", Game: "1.e4 c5 2.Nf3 d6 *"},
+		{Date: "2022.01.01", Result: "1-0", Game: "1.d4 d5 1-0"},
+	}
+
+	start, err := Start("", "1.e4 c5 2.Nf3")
+	if err != nil {
+		t.Fatalf("An error occured playing the moves: %v", err)
+	}
+
+	builder := NewBuilder(start, 2)
+	for _, game := range games {
+		builder.Add(game)
+	}
+
+	result := builder.Tree()
+
+	if result.Games != 3 || len(result.Moves) != 2 {
+		t.Fatalf("Incorrect Result: \nresult: %d games %d moves \nexpected: %d games %d moves", result.Games, len(result.Moves), 3, 2)
+	}
+
+	d6 := result.Moves[0]
+	if d6.Move != "d6" || d6.Games != 2 || d6.Score != 25 || d6.AverageElo != 2625 || d6.LastPlayed != "2021.05.01" {
+		t.Errorf("Incorrect Result: \nresult: %+v \nexpected: %v", d6, "d6 2 games 25% 2625 2021.05.01")
+	}
+
+	if len(d6.Moves) != 1 || d6.Moves[0].Move != "d4" || d6.Moves[0].Score != 100 {
+		t.Errorf("Incorrect Result: \nresult: %+v \nexpected: %v", d6.Moves, "d4 100%")
+	}
+
+	nc6 := result.Moves[1]
+	if nc6.Move != "Nc6" || nc6.Score != 100 || nc6.LastPlayed != "2019.??.??" {
+		t.Errorf("Incorrect Result: \nresult: %+v \nexpected: %v", nc6, "Nc6 100% 2019.??.??")
+	}
+
+	var sb strings.Builder
+	err = result.WriteText(&sb)
+	if err != nil {
+		t.Fatalf("An error occured writing the tree: %v", err)
+	}
+
+	if !strings.Contains(sb.String(), "  3.d4") {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", sb.String(), "  3.d4")
+	}
+}
+
+func TestStart(t *testing.T) {
+	fen := "4k3/8/8/8/8/8/4P3/4K3 w - - 0 1"
+
+	result, err := Start(fen, "1.e4 Kd7")
+	if err != nil {
+		t.Fatalf("An error occured playing the moves: %v", err)
+	}
+
+	expected := "8/3k4/8/8/4P3/8/8/4K3 w - - 1 2"
+	if result.FEN() != expected {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", result.FEN(), expected)
+	}
+
+	_, err = Start("", "1.e5")
+	if err == nil {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", err, "illegal move")
+	}
+}
@@ -1,54 +1,73 @@
 package types
 
+// Tag is a tag pair the game has no field for, a game keeps them in
+// ExtraTags in the order they were read.
+type Tag struct {
+	Name  string
+	Value string
+}
+
 type Game struct {
-	Event     string
-	Site      string
-	Date      string
-	Round     string
-	White     string
-	Black     string
-	Result    string
-	BlackElo  int
-	ECO       string
-	EventDate string
-	WhiteElo  int
-	Source    string
-	FEN       string
-	Game      string
+	Event       string
+	Site        string
+	Date        string
+	Round       string
+	White       string
+	Black       string
+	Result      string
+	BlackElo    int
+	ECO         string
+	Opening     string
+	Variation   string
+	EventDate   string
+	WhiteElo    int
+	Source      string
+	TimeControl string
+	FEN         string
+	Game        string
+	ExtraTags   []Tag
 }
 
 type GameParams struct {
-	Event     string
-	Site      string
-	Date      string
-	Round     string
-	White     string
-	Black     string
-	Result    string
-	BlackElo  int
-	ECO       string
-	EventDate string
-	WhiteElo  int
-	Source    string
-	FEN       string
-	Game      string
+	Event       string
+	Site        string
+	Date        string
+	Round       string
+	White       string
+	Black       string
+	Result      string
+	BlackElo    int
+	ECO         string
+	Opening     string
+	Variation   string
+	EventDate   string
+	WhiteElo    int
+	Source      string
+	TimeControl string
+	FEN         string
+	Game        string
+	ExtraTags   []Tag
 }
 
 func NewGame(params GameParams) *Game {
 	return &Game{
-		Event:     params.Event,
-		Site:      params.Site,
-		Date:      params.Date,
-		Round:     params.Round,
-		White:     params.White,
-		Black:     params.Black,
-		Result:    params.Result,
-		BlackElo:  params.BlackElo,
-		ECO:       params.ECO,
-		EventDate: params.EventDate,
-		WhiteElo:  params.WhiteElo,
-		Source:    params.Source,
-		FEN:       params.FEN,
-		Game:      params.Game,
+		Event:       params.Event,
+		Site:        params.Site,
+		Date:        params.Date,
+		Round:       params.Round,
+		White:       params.White,
+		Black:       params.Black,
+		Result:      params.Result,
+		BlackElo:    params.BlackElo,
+		ECO:         params.ECO,
+		Opening:     params.Opening,
+		Variation:   params.Variation,
+		EventDate:   params.EventDate,
+		WhiteElo:    params.WhiteElo,
+		Source:      params.Source,
+		TimeControl: params.TimeControl,
+		FEN:         params.FEN,
+		Game:        params.Game,
+		ExtraTags:   params.ExtraTags,
 	}
 }
@@ -0,0 +1,352 @@
+package validate
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gavink97/pgn-tools/internal/chess"
+	"github.com/gavink97/pgn-tools/internal/global"
+	"github.com/gavink97/pgn-tools/internal/parser"
+)
+
+// Problem is a defect found at a line of an input.
+type Problem struct {
+	Path    string
+	Line    int
+	Message string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s:%d: %s", p.Path, p.Line, p.Message)
+}
+
+type Line struct {
+	Number int
+	Text   string
+}
+
+// RawGame is a game as written in the input, the lines keep their line
+// numbers so problems can be located.
+type RawGame struct {
+	Tags     []Line
+	Movetext []Line
+}
+
+// Start is the line number of the first line of the game.
+func (g *RawGame) Start() int {
+	if len(g.Tags) > 0 {
+		return g.Tags[0].Number
+	}
+	if len(g.Movetext) > 0 {
+		return g.Movetext[0].Number
+	}
+	return 0
+}
+
+// ReadGames splits an input into games, a game ends where the tag pairs of
+// the next game start and a game without movetext ends at an Event tag after
+// a blank line. Blank lines and % escape lines are dropped.
+func ReadGames(r io.Reader) ([]*RawGame, error) {
+	var games []*RawGame
+	game := &RawGame{}
+	inComment := false
+	blank := false
+	number := 0
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		number++
+		text := scanner.Text()
+		trimmed := strings.TrimSpace(text)
+
+		if trimmed == "" {
+			blank = true
+			continue
+		}
+
+		if strings.HasPrefix(text, "%") {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "[") && !inComment {
+			newGame := blank && len(game.Tags) > 0 && strings.HasPrefix(trimmed, "[Event ")
+			if len(game.Movetext) > 0 || newGame {
+				games = append(games, game)
+				game = &RawGame{}
+			}
+
+			blank = false
+			game.Tags = append(game.Tags, Line{Number: number, Text: trimmed})
+			continue
+		}
+
+		blank = false
+		inComment = commentOpen(trimmed, inComment)
+		game.Movetext = append(game.Movetext, Line{Number: number, Text: text})
+	}
+
+	if len(game.Tags) > 0 || len(game.Movetext) > 0 {
+		games = append(games, game)
+	}
+
+	return games, scanner.Err()
+}
+
+// commentOpen reports whether a brace comment is open at the end of the
+// line, rest of line comments end with the line.
+func commentOpen(line string, inComment bool) bool {
+	for i := 0; i < len(line); i++ {
+		switch {
+		case inComment:
+			inComment = line[i] != '}'
+		case line[i] == '{':
+			inComment = true
+		case line[i] == ';':
+			return false
+		}
+	}
+
+	return inComment
+}
+
+// Input validates every game of an input and returns the problems found and
+// the number of games read.
+func Input(path string) ([]Problem, int, error) {
+	reader, err := parser.OpenInput(path)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	games, err := ReadGames(reader)
+	err = errors.Join(err, reader.Close())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var problems []Problem
+	for _, game := range games {
+		problems = append(problems, Game(path, game)...)
+	}
+
+	return problems, len(games), nil
+}
+
+var tagPattern = regexp.MustCompile(`^\[([A-Za-z0-9_]+)\s+"((?:[^"\\]|\\.)*)"\s*\]$`)
+
+var datePattern = regexp.MustCompile(`^(\d{4}|\?{4})\.(\d{2}|\?{2})\.(\d{2}|\?{2})$`)
+
+var resultValues = []string{"1-0", "0-1", "1/2-1/2", "*"}
+
+// Game validates the tag pairs and movetext of a game.
+func Game(path string, game *RawGame) []Problem {
+	var problems []Problem
+	report := func(line int, format string, args ...any) {
+		problems = append(problems, Problem{Path: path, Line: line, Message: fmt.Sprintf(format, args...)})
+	}
+
+	tags := map[string]string{}
+	resultLine := 0
+
+	for _, line := range game.Tags {
+		match := tagPattern.FindStringSubmatch(line.Text)
+		if match == nil {
+			report(line.Number, "malformed tag pair: %s", line.Text)
+			continue
+		}
+
+		name, value := match[1], match[2]
+
+		if _, exists := tags[name]; exists {
+			report(line.Number, "duplicate tag: %s", name)
+		}
+		tags[name] = value
+
+		switch name {
+		case "Date", "EventDate", "UTCDate":
+			if !ValidDate(value) {
+				report(line.Number, "bad date in %s: %q", name, value)
+			}
+		case "Result":
+			resultLine = line.Number
+			if !validResult(value) {
+				report(line.Number, "bad result: %q", value)
+			}
+		case "WhiteElo", "BlackElo":
+			if _, err := strconv.Atoi(value); err != nil && value != "" && value != "-" && value != "?" {
+				report(line.Number, "bad rating in %s: %q", name, value)
+			}
+		}
+	}
+
+	if len(game.Tags) > 0 && len(game.Movetext) == 0 {
+		report(game.Tags[len(game.Tags)-1].Number, "missing movetext")
+		return problems
+	}
+
+	marker, markerLine := checkMovetext(game, tags["FEN"], report)
+
+	result, hasResult := tags["Result"]
+	switch {
+	case marker == "":
+		report(game.Movetext[len(game.Movetext)-1].Number, "missing termination marker")
+	case hasResult && validResult(result) && result != marker:
+		report(markerLine, "termination marker %s does not match the Result tag %s on line %d", marker, result, resultLine)
+	}
+
+	return problems
+}
+
+// checkMovetext replays the moves including variations and reports moves
+// that can not be played. It returns the termination marker of the game.
+func checkMovetext(game *RawGame, fen string, report func(int, string, ...any)) (string, int) {
+	var sb strings.Builder
+	var starts []int
+
+	for _, line := range game.Movetext {
+		starts = append(starts, sb.Len())
+		sb.WriteString(line.Text)
+		sb.WriteByte('\n')
+	}
+
+	movetext := sb.String()
+	if language, exists := chess.Languages[global.InputLang]; exists {
+		movetext = parser.TranslateMovetext(movetext, language, chess.English)
+	}
+
+	// tokens are located by searching their text from the end of the
+	// previous token
+	cursor := 0
+	lineOf := func(text string) int {
+		start := cursor
+		if idx := strings.Index(movetext[cursor:], text); idx != -1 {
+			start = cursor + idx
+			cursor = start + len(text)
+		}
+
+		i := len(starts) - 1
+		for i > 0 && starts[i] > start {
+			i--
+		}
+		return game.Movetext[i].Number
+	}
+
+	pos := chess.StartingPosition()
+	if fen != "" {
+		p, err := chess.ParseFEN(fen)
+		if err != nil {
+			report(game.Start(), "bad FEN tag: %v", err)
+			pos = nil
+		} else {
+			pos = p
+		}
+	}
+
+	type line struct{ pos, prev *chess.Position }
+	current := line{pos: pos}
+	var stack []line
+
+	marker, markerLine := "", 0
+
+	for _, token := range parser.ParseMovetext(movetext) {
+		number := lineOf(token.Text)
+
+		switch token.Kind {
+		case parser.VariationStartToken:
+			stack = append(stack, current)
+			current = line{pos: current.prev}
+
+		case parser.VariationEndToken:
+			if len(stack) == 0 {
+				report(number, "unexpected end of variation")
+				continue
+			}
+			current = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+		case parser.ResultToken:
+			if len(stack) == 0 {
+				marker, markerLine = token.Text, number
+			}
+
+		case parser.MoveToken:
+			if current.pos == nil {
+				continue
+			}
+
+			move, err := current.pos.ParseSAN(token.Text)
+			if err != nil {
+				report(number, "%s %s", moveError(err), current.pos.Numbered(token.Text))
+				current = line{}
+				continue
+			}
+
+			current = line{pos: current.pos.Play(move), prev: current.pos}
+		}
+	}
+
+	if len(stack) > 0 {
+		report(game.Movetext[len(game.Movetext)-1].Number, "unclosed variation")
+	}
+
+	return marker, markerLine
+}
+
+func moveError(err error) string {
+	switch {
+	case errors.Is(err, chess.ErrAmbiguousMove):
+		return "ambiguous move"
+	case errors.Is(err, chess.ErrIllegalMove):
+		return "illegal move"
+	default:
+		return "invalid move"
+	}
+}
+
+func validResult(result string) bool {
+	for _, value := range resultValues {
+		if result == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidDate reports whether a date is in the YYYY.MM.DD format with question
+// marks for unknown parts, known parts have to form a real date.
+func ValidDate(date string) bool {
+	match := datePattern.FindStringSubmatch(date)
+	if match == nil {
+		return false
+	}
+
+	year, month, day := match[1], match[2], match[3]
+
+	if month != "??" {
+		m, _ := strconv.Atoi(month)
+		if m < 1 || m > 12 {
+			return false
+		}
+	}
+
+	if day != "??" {
+		d, _ := strconv.Atoi(day)
+		if d < 1 || d > 31 {
+			return false
+		}
+	}
+
+	if year != "????" && month != "??" && day != "??" {
+		_, err := time.Parse("2006.01.02", date)
+		return err == nil
+	}
+
+	return true
+}
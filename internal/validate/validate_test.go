@@ -0,0 +1,107 @@
+package validate
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	sample := `[Event "Broken"]
+[Date "2023.02.30"]
+[White "A"
+[Result "1-0"]
+
+1.e4 e5 2.Nf3 Nc6 3.Bb5 {a comment
+over lines} a6 4.Bxc7 Nf6
+5.O-O 0-1
+
+[Event "Variation"]
+[Date "2023.??.??"]
+[Result "*"]
+
+1.e4 (1.d4 d5 2.Nf6) e5 *
+
+[Event "Ambiguous"]
+[Date "2023.1.5"]
+[Result "1/2-1/2"]
+[Result "1/2-1/2"]
+[FEN "4k3/8/8/8/8/8/4K3/R6R w - - 0 1"]
+
+1.Rd1 Kd8
+`
+
+	games, err := ReadGames(strings.NewReader(sample))
+	if err != nil {
+		t.Fatalf("An error occured reading games: %v", err)
+	}
+
+	if len(games) != 3 {
+		t.Fatalf("Incorrect Result: \nresult: %v \nexpected: %v", len(games), 3)
+	}
+
+	var result []string
+	for _, game := range games {
+		for _, problem := range Game("games.pgn", game) {
+			result = append(result, problem.String())
+		}
+	}
+
+	expected := []string{
+		`games.pgn:2: bad date in Date: "2023.02.30"`,
+		`games.pgn:3: malformed tag pair: [White "A"`,
+		`games.pgn:7: illegal move 4. Bxc7`,
+		`games.pgn:8: termination marker 0-1 does not match the Result tag 1-0 on line 4`,
+		`games.pgn:14: illegal move 2. Nf6`,
+		`games.pgn:17: bad date in Date: "2023.1.5"`,
+		`games.pgn:19: duplicate tag: Result`,
+		`games.pgn:22: ambiguous move 1. Rd1`,
+		`games.pgn:22: missing termination marker`,
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", strings.Join(result, "\n"), strings.Join(expected, "\n"))
+	}
+}
+
+func TestReadGamesTagsOnly(t *testing.T) {
+	sample := `[Event "Tags only"]
+[Result "*"]
+
+[Event "Next"]
+[Result "*"]
+
+1.e4 *
+`
+
+	games, err := ReadGames(strings.NewReader(sample))
+	if err != nil {
+		t.Fatalf("An error occured reading games: %v", err)
+	}
+
+	if len(games) != 2 || len(games[0].Tags) != 2 || games[1].Start() != 4 {
+		t.Fatalf("Incorrect Result: \nresult: %v games \nexpected: %v games starting on lines 1 and 4", len(games), 2)
+	}
+
+	for _, problem := range Game("games.pgn", games[1]) {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", problem, "no problems")
+	}
+}
+
+func TestValidDate(t *testing.T) {
+	samples := map[string]bool{
+		"2024.02.29": true,
+		"2023.02.29": false,
+		"2023.??.??": true,
+		"????.??.??": true,
+		"2023.13.??": false,
+		"2023-01-05": false,
+		"":           false,
+	}
+
+	for date, expected := range samples {
+		if ValidDate(date) != expected {
+			t.Errorf("Incorrect Result for %q: \nresult: %v \nexpected: %v", date, !expected, expected)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package writer
+
+import (
+	"encoding/csv"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gavink97/pgn-tools/internal/parser"
+	"github.com/gavink97/pgn-tools/internal/types"
+)
+
+var DefaultColumns = []string{"event", "site", "date", "round", "white", "black", "result", "whiteelo",
+	"blackelo", "eco"}
+
+// ratings of 0 or less are unknown, an unreadable Elo tag is read as -1, and
+// written as empty cells
+var ratingColumns = []string{"whiteelo", "blackelo", "elo", "avgelo"}
+
+// csvFormat writes a header row followed by one row per game, the columns
+// are query keys so computed keys such as avgelo, opening and plies work too.
+type csvFormat struct {
+	Columns []string
+	Comma   rune
+}
+
+func NewCSVFormat(columns []string, comma rune) (Format, error) {
+	if len(columns) == 0 {
+		columns = DefaultColumns
+	}
+
+	for i, column := range columns {
+		columns[i] = strings.ToLower(strings.TrimSpace(column))
+
+		_, err := parser.GameValues(&types.Game{}, columns[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return csvFormat{Columns: columns, Comma: comma}, nil
+}
+
+func (f csvFormat) writeRow(out io.Writer, row []string) error {
+	w := csv.NewWriter(out)
+	w.Comma = f.Comma
+
+	err := w.Write(row)
+	if err != nil {
+		return err
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+func (f csvFormat) Begin(out io.Writer) error {
+	return f.writeRow(out, f.Columns)
+}
+
+func (f csvFormat) End(out io.Writer) error { return nil }
+
+func (f csvFormat) Extension() string {
+	if f.Comma == '\t' {
+		return ".tsv"
+	}
+	return ".csv"
+}
+
+func (f csvFormat) WriteGame(out io.Writer, game *types.Game, index int) error {
+	row := make([]string, len(f.Columns))
+
+	for i, column := range f.Columns {
+		values, err := parser.GameValues(game, column)
+		if err != nil {
+			return err
+		}
+
+		if slices.Contains(ratingColumns, column) {
+			values = slices.DeleteFunc(values, func(value string) bool {
+				rating, err := strconv.Atoi(value)
+				return err == nil && rating <= 0
+			})
+		}
+
+		row[i] = strings.Join(values, "; ")
+	}
+
+	return f.writeRow(out, row)
+}
@@ -0,0 +1,64 @@
+package writer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gavink97/pgn-tools/internal/types"
+)
+
+func TestCSVFormat(t *testing.T) {
+	game := &types.Game{
+		White:    `O"Brien, Sean`,
+		Black:    "Murphy, Liam",
+		WhiteElo: 2210,
+		BlackElo: -1,
+		Result:   "1-0",
+		Game:     "1. e4 e5 2. Nf3 1-0",
+	}
+
+	samples := []struct {
+		format   string
+		expected string
+	}{
+		{
+			format: CSV,
+			expected: "white,black,whiteelo,blackelo,plies\n" +
+				`"O""Brien, Sean","Murphy, Liam",2210,,3` + "\n",
+		},
+		{
+			format: TSV,
+			expected: "white\tblack\twhiteelo\tblackelo\tplies\n" +
+				`"O""Brien, Sean"` + "\tMurphy, Liam\t2210\t\t3\n",
+		},
+	}
+
+	for _, sample := range samples {
+		format, err := ParseFormat(sample.format, Options{Columns: []string{"White", "black", "whiteelo", "blackelo", "plies"}})
+		if err != nil {
+			t.Fatalf("An error occured parsing format: %v", err)
+		}
+
+		var sb strings.Builder
+		sink := NewStreamSink(&sb, format)
+
+		err = sink.Write(game)
+		if err != nil {
+			t.Fatalf("An error occured writing game: %v", err)
+		}
+
+		err = sink.Close()
+		if err != nil {
+			t.Fatalf("An error occured closing sink: %v", err)
+		}
+
+		if sb.String() != sample.expected {
+			t.Errorf("Incorrect Result for %s: \nresult: %q \nexpected: %q", sample.format, sb.String(), sample.expected)
+		}
+	}
+
+	_, err := ParseFormat(CSV, Options{Columns: []string{"white", "unknown"}})
+	if err == nil {
+		t.Errorf("Expected an error parsing unknown column")
+	}
+}
@@ -0,0 +1,167 @@
+package writer
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/gavink97/pgn-tools/internal/chess"
+	"github.com/gavink97/pgn-tools/internal/parser"
+	"github.com/gavink97/pgn-tools/internal/types"
+)
+
+const (
+	// the position after the last move
+	FinalPosition = "final"
+	// the position after a number of plies
+	PlyPosition = "ply"
+	// every position once a move number has been played
+	AfterMovePositions = "after-move"
+	// every position of the game
+	EveryPosition = "every"
+)
+
+var EPDOpcodes = []string{"id", "bm", "c0"}
+
+// EPDFormat writes positions of the main line as EPD lines, or plain FEN
+// lines when FEN is set. The bm opcode holds the move played in the
+// position, id names the source, game number and ply, and c0 describes the
+// game.
+type EPDFormat struct {
+	Source  string
+	Mode    string
+	N       int
+	FEN     bool
+	Opcodes []string
+}
+
+func (f *EPDFormat) Begin(out io.Writer) error { return nil }
+func (f *EPDFormat) End(out io.Writer) error   { return nil }
+
+func (f *EPDFormat) Extension() string {
+	if f.FEN {
+		return ".fen"
+	}
+	return ".epd"
+}
+
+type epdPosition struct {
+	pos  *chess.Position
+	ply  int
+	next string
+}
+
+func (f *EPDFormat) WriteGame(out io.Writer, game *types.Game, index int) error {
+	for _, p := range f.positions(game) {
+		_, err := io.WriteString(out, f.line(game, index, p)+"\n")
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// positions replays the main line and returns the selected positions, the
+// replay stops at the first illegal move.
+func (f *EPDFormat) positions(game *types.Game) []epdPosition {
+	pos := chess.StartingPosition()
+	if game.FEN != "" {
+		p, err := chess.ParseFEN(game.FEN)
+		if err != nil {
+			return nil
+		}
+		pos = p
+	}
+
+	var positions []epdPosition
+
+	for ply, san := range append(parser.ParseMoves(game.Game), "") {
+		var move chess.Move
+		var err error
+
+		next := ""
+		if san != "" {
+			move, err = pos.ParseSAN(san)
+			if err == nil {
+				next = pos.SAN(move)
+			}
+		}
+
+		if f.selects(pos, ply, next == "") {
+			positions = append(positions, epdPosition{pos: pos, ply: ply, next: next})
+		}
+
+		if next == "" {
+			break
+		}
+
+		pos = pos.Play(move)
+	}
+
+	return positions
+}
+
+func (f *EPDFormat) selects(pos *chess.Position, ply int, last bool) bool {
+	switch f.Mode {
+	case PlyPosition:
+		return ply == f.N
+	case AfterMovePositions:
+		return pos.FullmoveNumber > f.N
+	case EveryPosition:
+		return true
+	default:
+		return last
+	}
+}
+
+var epdStringEscaper = strings.NewReplacer(`"`, "'", ";", ",")
+
+func (f *EPDFormat) line(game *types.Game, index int, p epdPosition) string {
+	// fen lines have no opcodes
+	if f.FEN {
+		return p.pos.FEN()
+	}
+
+	var sb strings.Builder
+	sb.WriteString(p.pos.EPD())
+
+	for _, opcode := range f.Opcodes {
+		switch opcode {
+		case "bm":
+			if p.next != "" {
+				fmt.Fprintf(&sb, " bm %s;", p.next)
+			}
+		case "id":
+			id := fmt.Sprintf("%s:%d:%d", filepath.Base(f.Source), index+1, p.ply)
+			fmt.Fprintf(&sb, ` id "%s";`, epdStringEscaper.Replace(id))
+		case "c0":
+			header := fmt.Sprintf("%s - %s, %s, %s, %s", game.White, game.Black, game.Event, game.Date, game.Result)
+			fmt.Fprintf(&sb, ` c0 "%s";`, epdStringEscaper.Replace(header))
+		}
+	}
+
+	return sb.String()
+}
+
+// ParseOpcodes validates a comma separated list of opcodes.
+func ParseOpcodes(list string) ([]string, error) {
+	var opcodes []string
+
+	for opcode := range strings.SplitSeq(list, ",") {
+		opcode = strings.ToLower(strings.TrimSpace(opcode))
+		if opcode == "" {
+			continue
+		}
+
+		if !slices.Contains(EPDOpcodes, opcode) {
+			return nil, fmt.Errorf("unknown opcode: %s, expected one of %s", opcode, strings.Join(EPDOpcodes, ", "))
+		}
+
+		opcodes = append(opcodes, opcode)
+	}
+
+	return opcodes, nil
+}
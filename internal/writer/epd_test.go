@@ -0,0 +1,70 @@
+package writer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEPDFormat(t *testing.T) {
+	samples := []struct {
+		format   *EPDFormat
+		expected []string
+	}{
+		{
+			format: &EPDFormat{Source: "games/tata.pgn", Mode: FinalPosition, Opcodes: EPDOpcodes},
+			expected: []string{
+				`r1bqkb1r/pppp1ppp/2n2n2/1B2p3/4P3/5N2/PPPP1PPP/RNBQK2R w KQkq - id "tata.pgn:1:6"; ` +
+					`c0 "Carlsen, Magnus - Giri, Anish, Tata Steel Masters, 2023.01.14, 1/2-1/2";`,
+			},
+		},
+		{
+			format: &EPDFormat{Mode: PlyPosition, N: 0, Opcodes: []string{"bm"}},
+			expected: []string{
+				"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - bm e4;",
+			},
+		},
+		{
+			format: &EPDFormat{Mode: AfterMovePositions, N: 2, FEN: true},
+			expected: []string{
+				"r1bqkbnr/pppp1ppp/2n5/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R w KQkq - 2 3",
+				"r1bqkbnr/pppp1ppp/2n5/1B2p3/4P3/5N2/PPPP1PPP/RNBQK2R b KQkq - 3 3",
+				"r1bqkb1r/pppp1ppp/2n2n2/1B2p3/4P3/5N2/PPPP1PPP/RNBQK2R w KQkq - 4 4",
+			},
+		},
+		{
+			format: &EPDFormat{Mode: EveryPosition},
+			expected: []string{
+				"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq -",
+				"rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq -",
+				"rnbqkbnr/pppp1ppp/8/4p3/4P3/8/PPPP1PPP/RNBQKBNR w KQkq -",
+				"rnbqkbnr/pppp1ppp/8/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R b KQkq -",
+				"r1bqkbnr/pppp1ppp/2n5/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R w KQkq -",
+				"r1bqkbnr/pppp1ppp/2n5/1B2p3/4P3/5N2/PPPP1PPP/RNBQK2R b KQkq -",
+				"r1bqkb1r/pppp1ppp/2n2n2/1B2p3/4P3/5N2/PPPP1PPP/RNBQK2R w KQkq -",
+			},
+		},
+	}
+
+	game := *sampleGame
+	game.Game = "1.e4 e5 2.Nf3 Nc6 3.Bb5 Nf6 1/2-1/2"
+
+	for _, sample := range samples {
+		var sb strings.Builder
+
+		err := sample.format.WriteGame(&sb, &game, 0)
+		if err != nil {
+			t.Fatalf("An error occured writing positions: %v", err)
+		}
+
+		result := strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n")
+		if strings.Join(result, "\n") != strings.Join(sample.expected, "\n") {
+			t.Errorf("Incorrect Result for %s: \nresult: %v \nexpected: %v", sample.format.Mode,
+				strings.Join(result, "\n"), strings.Join(sample.expected, "\n"))
+		}
+	}
+
+	_, err := ParseOpcodes("id,bm,pv")
+	if err == nil {
+		t.Errorf("Expected an error parsing unknown opcode")
+	}
+}
@@ -0,0 +1,151 @@
+package writer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/gavink97/pgn-tools/internal/chess"
+	"github.com/gavink97/pgn-tools/internal/types"
+)
+
+const (
+	PGN    = "pgn"
+	JSON   = "json"
+	NDJSON = "ndjson"
+	CSV    = "csv"
+	TSV    = "tsv"
+)
+
+// Format serializes games into an output. Begin is written before the first
+// game and End after the last, index counts the games written before.
+type Format interface {
+	Begin(out io.Writer) error
+	WriteGame(out io.Writer, game *types.Game, index int) error
+	End(out io.Writer) error
+	Extension() string
+}
+
+// Options configure the formats. Columns are only used by csv and tsv and
+// default to DefaultColumns, Notation is the notation of pgn movetext and
+// Language the language of its piece letters.
+type Options struct {
+	Columns  []string
+	Notation string
+	Language string
+}
+
+func ParseFormat(name string, options Options) (Format, error) {
+	err := verifyNotation(options.Notation)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Language != "" {
+		_, err = chess.ParseLanguage(options.Language)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch strings.ToLower(name) {
+	case PGN, "":
+		return pgnFormat{options}, nil
+	case JSON:
+		return jsonFormat{options}, nil
+	case NDJSON, "jsonl":
+		return ndjsonFormat{options}, nil
+	case CSV:
+		return NewCSVFormat(options.Columns, ',')
+	case TSV:
+		return NewCSVFormat(options.Columns, '\t')
+	default:
+		return nil, fmt.Errorf("unknown format: %s, expected %s, %s, %s, %s or %s", name, PGN, JSON, NDJSON, CSV, TSV)
+	}
+}
+
+// FormatOf returns the format named by the extension of path, paths without a
+// known extension are pgn.
+func FormatOf(path string, options Options) (Format, error) {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(trimGzip(path)), "."))
+
+	switch ext {
+	case JSON, NDJSON, "jsonl", CSV, TSV:
+		return ParseFormat(ext, options)
+	default:
+		return ParseFormat(PGN, options)
+	}
+}
+
+// FormatPath replaces the extension of path with the extension of the format,
+// a gzip extension is kept.
+func FormatPath(path string, format Format) string {
+	trimmed := trimGzip(path)
+	return strings.TrimSuffix(trimmed, filepath.Ext(trimmed)) + format.Extension() + path[len(trimmed):]
+}
+
+type pgnFormat struct {
+	Options Options
+}
+
+func (pgnFormat) Begin(out io.Writer) error { return nil }
+func (pgnFormat) End(out io.Writer) error   { return nil }
+func (pgnFormat) Extension() string         { return ".pgn" }
+
+func (f pgnFormat) WriteGame(out io.Writer, game *types.Game, index int) error {
+	_, err := io.WriteString(out, formatPGN(game, f.Options))
+	return err
+}
+
+// jsonFormat writes the games as a single json array.
+type jsonFormat struct {
+	Options Options
+}
+
+func (jsonFormat) Begin(out io.Writer) error {
+	_, err := io.WriteString(out, "[")
+	return err
+}
+
+func (jsonFormat) End(out io.Writer) error {
+	_, err := io.WriteString(out, "\n]\n")
+	return err
+}
+
+func (jsonFormat) Extension() string { return ".json" }
+
+func (f jsonFormat) WriteGame(out io.Writer, game *types.Game, index int) error {
+	data, err := json.Marshal(NewJSONGame(game, f.Options))
+	if err != nil {
+		return err
+	}
+
+	sep := ",\n"
+	if index == 0 {
+		sep = "\n"
+	}
+
+	_, err = io.WriteString(out, sep+string(data))
+	return err
+}
+
+// ndjsonFormat writes one json object per line.
+type ndjsonFormat struct {
+	Options Options
+}
+
+func (ndjsonFormat) Begin(out io.Writer) error { return nil }
+func (ndjsonFormat) End(out io.Writer) error   { return nil }
+func (ndjsonFormat) Extension() string         { return ".ndjson" }
+
+func (f ndjsonFormat) WriteGame(out io.Writer, game *types.Game, index int) error {
+	data, err := json.Marshal(NewJSONGame(game, f.Options))
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(out, string(data)+"\n")
+	return err
+}
@@ -0,0 +1,126 @@
+package writer
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/gavink97/pgn-tools/internal/parser"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestFormatPGNGolden(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "*.pgn"))
+	if err != nil {
+		t.Fatalf("An error occured finding test data: %v", err)
+	}
+
+	for _, input := range inputs {
+		games, err := parser.ParsePGN(input)
+		if err != nil {
+			t.Fatalf("An error occured parsing %s: %v", input, err)
+		}
+
+		var sb strings.Builder
+		for _, game := range games {
+			sb.WriteString(formatPGN(game, Options{}))
+		}
+
+		golden := strings.TrimSuffix(input, ".pgn") + ".golden"
+
+		if *update {
+			err = os.WriteFile(golden, []byte(sb.String()), 0600)
+			if err != nil {
+				t.Fatalf("An error occured updating %s: %v", golden, err)
+			}
+		}
+
+		expected, err := os.ReadFile(golden)
+		if err != nil {
+			t.Fatalf("An error occured reading %s: %v", golden, err)
+		}
+
+		if sb.String() != string(expected) {
+			t.Errorf("Incorrect Result for %s: \nresult: %v \nexpected: %v", input, sb.String(), string(expected))
+		}
+
+		for _, line := range strings.Split(sb.String(), "\n") {
+			if utf8.RuneCountInString(line) > lineWidth {
+				t.Errorf("Line longer than %d columns in %s: %s", lineWidth, input, line)
+			}
+		}
+
+		// the export format parses back into the same games
+		reparsed, err := parser.ParsePGN(golden)
+		if err != nil {
+			t.Fatalf("An error occured parsing %s: %v", golden, err)
+		}
+
+		for i, game := range reparsed {
+			if game.White != games[i].White || game.Event != games[i].Event || game.FEN != games[i].FEN ||
+				!reflect.DeepEqual(game.ExtraTags, games[i].ExtraTags) {
+				t.Errorf("Incorrect Result for %s: \nresult: %v \nexpected: %v", golden, *game, *games[i])
+			}
+		}
+	}
+}
+
+func TestFormatMovetextNotation(t *testing.T) {
+	game := *sampleGame
+	game.Game = "1.e4 e5 2.Nf3 (2.f4 exf4 3.Nf3) 2...Nc6 3.Bb5 a6 4.O-O {Castles} 1/2-1/2"
+
+	samples := map[string]string{
+		SAN:      "1. e4 e5 2. Nf3 (2. f4 exf4 3. Nf3) 2... Nc6 3. Bb5 a6 4. O-O {Castles} 1/2-1/2",
+		UCI:      "1. e2e4 e7e5 2. g1f3 (2. f2f4 e5f4 3. g1f3) 2... b8c6 3. f1b5 a7a6 4. e1g1\n{Castles} 1/2-1/2",
+		LAN:      "1. e2-e4 e7-e5 2. Ng1-f3 (2. f2-f4 e5xf4 3. Ng1-f3) 2... Nb8-c6 3. Bf1-b5 a7-a6\n4. O-O {Castles} 1/2-1/2",
+		Figurine: "1. e4 e5 2. ♘f3 (2. f4 exf4 3. ♘f3) 2... ♘c6 3. ♗b5 a6 4. O-O {Castles} 1/2-1/2",
+	}
+
+	for notation, expected := range samples {
+		result := formatMovetext(&game, Options{Notation: notation})
+		if result != expected {
+			t.Errorf("Incorrect Result for %s: \nresult: %v \nexpected: %v", notation, result, expected)
+		}
+	}
+
+	_, err := ParseFormat(PGN, Options{Notation: "descriptive"})
+	if err == nil {
+		t.Errorf("Expected an error parsing unknown notation")
+	}
+}
+
+func TestFormatMovetextLanguage(t *testing.T) {
+	game := *sampleGame
+	game.Game = "1.e4 e5 2.Nf3 (2.f4 exf4 3.Nf3) 2...Nc6 3.Bb5 a6 4.O-O {Castles} 1/2-1/2"
+
+	samples := []struct {
+		options  Options
+		expected string
+	}{
+		{
+			options:  Options{Language: "de"},
+			expected: "1. e4 e5 2. Sf3 (2. f4 exf4 3. Sf3) 2... Sc6 3. Lb5 a6 4. O-O {Castles} 1/2-1/2",
+		},
+		{
+			options:  Options{Notation: LAN, Language: "es"},
+			expected: "1. e2-e4 e7-e5 2. Cg1-f3 (2. f2-f4 e5xf4 3. Cg1-f3) 2... Cb8-c6 3. Af1-b5 a7-a6\n4. O-O {Castles} 1/2-1/2",
+		},
+	}
+
+	for _, sample := range samples {
+		result := formatMovetext(&game, sample.options)
+		if result != sample.expected {
+			t.Errorf("Incorrect Result for %s: \nresult: %v \nexpected: %v", sample.options.Language, result, sample.expected)
+		}
+	}
+
+	_, err := ParseFormat(PGN, Options{Language: "xx"})
+	if err == nil {
+		t.Errorf("Expected an error parsing unknown language")
+	}
+}
@@ -0,0 +1,103 @@
+package writer
+
+import (
+	"regexp"
+
+	"github.com/gavink97/pgn-tools/internal/chess"
+	"github.com/gavink97/pgn-tools/internal/parser"
+	"github.com/gavink97/pgn-tools/internal/types"
+)
+
+type JSONComment struct {
+	Ply  int    `json:"ply"`
+	Text string `json:"text"`
+}
+
+// JSONGame is the json representation of a game. Moves holds the main line
+// in SAN and UCI, a comment belongs to the ply it follows with 0 before the
+// first move, and Clocks holds the [%clk] value of every move if any are set.
+type JSONGame struct {
+	Tags     map[string]string `json:"tags"`
+	Moves    []string          `json:"moves"`
+	UCI      []string          `json:"uci"`
+	Comments []JSONComment     `json:"comments,omitempty"`
+	Clocks   []string          `json:"clocks,omitempty"`
+	Movetext string            `json:"movetext"`
+}
+
+var clockPattern = regexp.MustCompile(`\[%clk\s+([0-9:.]+)\]`)
+
+// NewJSONGame converts the game, the options only apply to the movetext.
+func NewJSONGame(game *types.Game, options Options) *JSONGame {
+	j := &JSONGame{
+		Tags:     map[string]string{},
+		Moves:    []string{},
+		UCI:      []string{},
+		Movetext: formatMovetext(game, options),
+	}
+
+	for _, t := range gameTags(game) {
+		j.Tags[t.Name] = t.Value
+	}
+
+	pos := chess.StartingPosition()
+	if game.FEN != "" {
+		p, err := chess.ParseFEN(game.FEN)
+		if err == nil {
+			pos = p
+		}
+	}
+
+	// the main line is replayed so the moves are written in normalized SAN,
+	// moves after an illegal move are kept as written without UCI
+	legal := true
+	depth := 0
+	var clocks []string
+	hasClock := false
+
+	for _, token := range parser.ParseMovetext(game.Game) {
+		switch token.Kind {
+		case parser.VariationStartToken:
+			depth++
+		case parser.VariationEndToken:
+			depth--
+		}
+
+		if depth != 0 {
+			continue
+		}
+
+		switch token.Kind {
+		case parser.MoveToken:
+			san := token.Text
+
+			if legal {
+				move, err := pos.ParseSAN(san)
+				if err != nil {
+					legal = false
+				} else {
+					san = pos.SAN(move)
+					j.UCI = append(j.UCI, move.UCI())
+					pos = pos.Play(move)
+				}
+			}
+
+			j.Moves = append(j.Moves, san)
+			clocks = append(clocks, "")
+
+		case parser.CommentToken:
+			if match := clockPattern.FindStringSubmatch(token.Text); match != nil && len(clocks) > 0 {
+				clocks[len(clocks)-1] = match[1]
+				hasClock = true
+			}
+
+			j.Comments = append(j.Comments, JSONComment{Ply: len(j.Moves), Text: token.Text})
+		}
+	}
+
+	if hasClock {
+		j.Clocks = clocks
+	}
+
+	return j
+}
@@ -0,0 +1,98 @@
+package writer
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gavink97/pgn-tools/internal/types"
+)
+
+func TestNewJSONGame(t *testing.T) {
+	game := &types.Game{
+		Event:     "Titled Arena",
+		Site:      "lichess.org",
+		Date:      "2024.03.02",
+		Round:     "?",
+		White:     "Carlsen, Magnus",
+		Black:     "Nakamura, Hikaru",
+		Result:    "1-0",
+		ExtraTags: []types.Tag{{Name: "WhiteTitle", Value: "GM"}, {Name: "Termination", Value: "Normal"}},
+		Game: `1. e4 {[%clk 0:02:59]} 1... e5 {[%clk 0:02:58]} 2. Nf3 (2. f4 exf4)
+2... Nc6 {[%clk 0:02:57]} 3. Bb5 {Ruy Lopez} a6 4. Bxc6 dxc6 5. O-O f6 6. d4
+exd4 7. Nxd4 c5 8. Nb3 Qxd1 9. Rxd1 1-0`,
+	}
+
+	result := NewJSONGame(game, Options{})
+
+	moves := []string{"e4", "e5", "Nf3", "Nc6", "Bb5", "a6", "Bxc6", "dxc6", "O-O", "f6", "d4",
+		"exd4", "Nxd4", "c5", "Nb3", "Qxd1", "Rxd1"}
+	if !reflect.DeepEqual(result.Moves, moves) {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", result.Moves, moves)
+	}
+
+	if result.UCI[0] != "e2e4" || result.UCI[8] != "e1g1" || len(result.UCI) != len(moves) {
+		t.Errorf("Incorrect Result: \nresult: %v", result.UCI)
+	}
+
+	clocks := []string{"0:02:59", "0:02:58", "", "0:02:57"}
+	if !reflect.DeepEqual(result.Clocks[:4], clocks) || len(result.Clocks) != len(moves) {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", result.Clocks, clocks)
+	}
+
+	comment := JSONComment{Ply: 5, Text: "Ruy Lopez"}
+	if len(result.Comments) != 4 || result.Comments[3] != comment {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", result.Comments, comment)
+	}
+
+	if result.Tags["White"] != "Carlsen, Magnus" || result.Tags["Round"] != "?" || result.Tags["WhiteTitle"] != "GM" {
+		t.Errorf("Incorrect Result: \nresult: %v", result.Tags)
+	}
+}
+
+func TestJSONFormat(t *testing.T) {
+	for _, name := range []string{JSON, NDJSON} {
+		format, err := ParseFormat(name, Options{})
+		if err != nil {
+			t.Fatalf("An error occured parsing format: %v", err)
+		}
+
+		var sb strings.Builder
+		sink := NewStreamSink(&sb, format)
+
+		for range 2 {
+			err = sink.Write(sampleGame)
+			if err != nil {
+				t.Fatalf("An error occured writing game: %v", err)
+			}
+		}
+
+		err = sink.Close()
+		if err != nil {
+			t.Fatalf("An error occured closing sink: %v", err)
+		}
+
+		var games []JSONGame
+
+		if name == JSON {
+			err = json.Unmarshal([]byte(sb.String()), &games)
+			if err != nil {
+				t.Fatalf("An error occured decoding json: %v", err)
+			}
+		} else {
+			for _, line := range strings.Split(strings.TrimSpace(sb.String()), "\n") {
+				var game JSONGame
+				err = json.Unmarshal([]byte(line), &game)
+				if err != nil {
+					t.Fatalf("An error occured decoding ndjson: %v", err)
+				}
+				games = append(games, game)
+			}
+		}
+
+		if len(games) != 2 || games[1].Tags["Black"] != "Giri, Anish" {
+			t.Errorf("Incorrect Result for %s: \nresult: %v", name, games)
+		}
+	}
+}
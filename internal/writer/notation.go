@@ -0,0 +1,108 @@
+package writer
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/gavink97/pgn-tools/internal/chess"
+)
+
+const (
+	SAN      = "san"
+	UCI      = "uci"
+	LAN      = "lan"
+	Figurine = "figurine"
+)
+
+var Notations = []string{SAN, UCI, LAN, Figurine}
+
+func verifyNotation(notation string) error {
+	if notation == "" || slices.Contains(Notations, notation) {
+		return nil
+	}
+
+	return fmt.Errorf("unknown notation: %s, expected one of %v", notation, Notations)
+}
+
+type positions struct {
+	pos  *chess.Position
+	prev *chess.Position
+}
+
+// moveConverter replays the movetext including its variations to rewrite
+// SAN moves in another notation. Once a move can not be replayed the rest of
+// its line is written as is.
+type moveConverter struct {
+	notation string
+	positions
+	stack []positions
+}
+
+// newMoveConverter returns nil when the movetext is written as is, the
+// methods of a nil converter leave the moves unchanged.
+func newMoveConverter(fen string, options Options) *moveConverter {
+	if options.Notation == "" || options.Notation == SAN {
+		return nil
+	}
+
+	pos := chess.StartingPosition()
+	if fen != "" {
+		p, err := chess.ParseFEN(fen)
+		if err != nil {
+			pos = nil
+		} else {
+			pos = p
+		}
+	}
+
+	return &moveConverter{
+		notation:  options.Notation,
+		positions: positions{pos: pos},
+	}
+}
+
+func (c *moveConverter) convert(san string) string {
+	if c == nil || c.pos == nil {
+		return san
+	}
+
+	move, err := c.pos.ParseSAN(san)
+	if err != nil {
+		c.pos, c.prev = nil, nil
+		return san
+	}
+
+	var text string
+	switch c.notation {
+	case UCI:
+		text = move.UCI()
+	case LAN:
+		text = c.pos.LAN(move)
+	case Figurine:
+		text = c.pos.Figurine(move)
+	default:
+		text = c.pos.SAN(move)
+	}
+
+	c.prev, c.pos = c.pos, c.pos.Play(move)
+	return text
+}
+
+// enter starts a variation, it replaces the last move played.
+func (c *moveConverter) enter() {
+	if c == nil {
+		return
+	}
+
+	c.stack = append(c.stack, c.positions)
+	c.positions = positions{pos: c.prev}
+}
+
+func (c *moveConverter) exit() {
+	if c == nil || len(c.stack) == 0 {
+		return
+	}
+
+	c.positions = c.stack[len(c.stack)-1]
+	c.stack = c.stack[:len(c.stack)-1]
+}
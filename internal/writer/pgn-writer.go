@@ -1,101 +1,433 @@
 package writer
 
 import (
+	"bufio"
+	"compress/gzip"
+	"errors"
 	"fmt"
-	"log"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 
-	"github.com/gavink97/pgn-tools/internal/global"
+	"github.com/gavink97/pgn-tools/internal/chess"
+	"github.com/gavink97/pgn-tools/internal/parser"
 	"github.com/gavink97/pgn-tools/internal/types"
 )
 
-func WritePGN(filename string, game any) {
-	f, err := os.OpenFile(filename, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0600)
+// Writer writes games through a buffer into a temporary file next to Path,
+// the temporary file replaces Path once the writer is closed so a failed run
+// never leaves a partial database behind. Paths ending in .gz are written
+// gzip compressed.
+type Writer struct {
+	Path    string
+	Format  Format
+	tmpPath string
+	file    *os.File
+	gz      *gzip.Writer
+	buf     *bufio.Writer
+	count   int
+}
+
+// NewWriter creates a writer of the format, a nil format writes pgn.
+func NewWriter(path string, format Format) (*Writer, error) {
+	if format == nil {
+		format = pgnFormat{}
+	}
+
+	dir, base := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+
+	file, err := os.CreateTemp(dir, "."+base+".tmp-*")
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Writer{
+		Path:    path,
+		Format:  format,
+		tmpPath: file.Name(),
+		buf:     bufio.NewWriter(nil),
+	}
+	w.open(file)
+
+	err = format.Begin(w.buf)
 	if err != nil {
-		log.Fatal(err)
+		return nil, errors.Join(err, w.Abort())
 	}
 
-	defer func() {
-		err = f.Close()
+	return w, nil
+}
+
+func (w *Writer) Write(game *types.Game) error {
+	if w.file == nil {
+		err := w.reopen()
 		if err != nil {
-			global.Logger.Error(fmt.Sprintf("an unexpected error occured closing file: %s", filename))
-			global.Logger.Error(err.Error())
-			os.Exit(1)
+			return err
 		}
-	}()
-
-	var content string
-	switch g := game.(type) {
-	case *types.Game:
-		content = formatPGN(g)
-	case []*types.Game:
-		var sb strings.Builder
-		for _, game := range g {
-			sb.WriteString(formatPGN(game))
+	}
+
+	err := w.Format.WriteGame(w.buf, game, w.count)
+	if err != nil {
+		return err
+	}
+
+	w.count++
+	return nil
+}
+
+func (w *Writer) WriteGames(games []*types.Game) error {
+	for _, game := range games {
+		err := w.Write(game)
+		if err != nil {
+			return err
 		}
-		content = sb.String()
-	default:
-		global.Logger.Error("unsupported type, expected *types.Game or []*types.Game")
-		global.Logger.Error("type: %s", g)
-		os.Exit(1)
 	}
 
-	_, err = f.WriteString(content)
+	return nil
+}
+
+// Suspend flushes the buffer and releases the file handle, the next write
+// reopens the temporary file. It lets callers with many outputs stay below
+// the open file limit.
+func (w *Writer) Suspend() error {
+	if w.file == nil {
+		return nil
+	}
+
+	err := w.buf.Flush()
+	if w.gz != nil {
+		err = errors.Join(err, w.gz.Close())
+		w.gz = nil
+	}
+	err = errors.Join(err, w.file.Close())
+	w.file = nil
+
+	return err
+}
+
+// open starts writing to file, a reopened compressed file gets a new gzip
+// member which readers concatenate with the previous ones.
+func (w *Writer) open(file *os.File) {
+	w.file = file
+
+	if !compressed(w.Path) {
+		w.buf.Reset(file)
+		return
+	}
+
+	w.gz = gzip.NewWriter(file)
+	w.buf.Reset(w.gz)
+}
+
+func (w *Writer) reopen() error {
+	file, err := os.OpenFile(w.tmpPath, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	w.open(file)
+	return nil
+}
+
+// Close flushes the games and moves the temporary file into place. On error
+// the temporary file is removed and Path is left untouched.
+func (w *Writer) Close() error {
+	if w.file == nil {
+		err := w.reopen()
+		if err != nil {
+			return errors.Join(err, w.Abort())
+		}
+	}
+
+	err := w.Format.End(w.buf)
+	if err == nil {
+		err = w.Suspend()
+	}
+	if err == nil {
+		err = os.Rename(w.tmpPath, w.Path)
+	}
+
 	if err != nil {
-		log.Fatal(err)
+		return errors.Join(err, w.Abort())
 	}
+
+	return nil
 }
 
-func formatPGN(game *types.Game) string {
-	out := fmt.Sprintf(`[Event "%s"]
-[Site "%s"]
-[Date "%s"]
-[Round "%s"]
-[White "%s"]
-[Black "%s"]
-[Result "%s"]`,
-		game.Event,
-		game.Site,
-		game.Date,
-		game.Round,
-		game.White,
-		game.Black,
-		game.Result,
-	)
+// Abort discards the written games and removes the temporary file.
+func (w *Writer) Abort() error {
+	if w.file != nil {
+		_ = w.file.Close()
+		w.file = nil
+		w.gz = nil
+	}
 
-	if game.WhiteElo != 0 {
-		out += "\n"
-		out += fmt.Sprintf(`[WhiteElo "%d"]`, game.WhiteElo)
+	err := os.Remove(w.tmpPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
 	}
 
-	if game.BlackElo != 0 {
-		out += "\n"
-		out += fmt.Sprintf(`[BlackElo "%d"]`, game.BlackElo)
+	return err
+}
+
+func compressed(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), parser.GzipExtension)
+}
+
+// trimGzip removes the gzip extension from path.
+func trimGzip(path string) string {
+	if compressed(path) {
+		return strings.TrimSuffix(path, filepath.Ext(path))
 	}
+	return path
+}
 
-	if game.EventDate != "" {
-		out += "\n"
-		out += fmt.Sprintf(`[EventDate "%s"]`, game.EventDate)
+// WriteGames writes the games to path in pgn with a Writer.
+func WriteGames(path string, games []*types.Game) error {
+	w, err := NewWriter(path, nil)
+	if err != nil {
+		return err
 	}
 
-	if game.ECO != "" {
-		out += "\n"
-		out += fmt.Sprintf(`[ECO "%s"]`, game.ECO)
+	err = w.WriteGames(games)
+	if err != nil {
+		return errors.Join(fmt.Errorf("unable to write %s: %w", path, err), w.Abort())
+	}
+
+	return w.Close()
+}
+
+type tag struct {
+	Name  string
+	Value string
+}
+
+// gameTags returns the tags of the game in export order: the seven tag
+// roster followed by the optional tags that are set and the extra tags in
+// the order they were read.
+func gameTags(game *types.Game) []tag {
+	tags := []tag{
+		{"Event", game.Event},
+		{"Site", game.Site},
+		{"Date", game.Date},
+		{"Round", game.Round},
+		{"White", game.White},
+		{"Black", game.Black},
+		{"Result", game.Result},
+	}
+
+	// an unreadable rating is read as -1 and left out like a missing one
+	if game.WhiteElo > 0 {
+		tags = append(tags, tag{"WhiteElo", strconv.Itoa(game.WhiteElo)})
+	}
+
+	if game.BlackElo > 0 {
+		tags = append(tags, tag{"BlackElo", strconv.Itoa(game.BlackElo)})
+	}
+
+	optional := []tag{
+		{"EventDate", game.EventDate},
+		{"ECO", game.ECO},
+		{"Opening", game.Opening},
+		{"Variation", game.Variation},
+		{"TimeControl", game.TimeControl},
 	}
 
 	if game.FEN != "" {
-		out += "\n"
-		out += fmt.Sprintf(`[FEN "%s"]`, game.FEN)
-		out += "\n"
-		out += `[SetUp "1"]`
+		optional = append(optional, tag{"SetUp", "1"}, tag{"FEN", game.FEN})
+	}
+
+	optional = append(optional, tag{"Source", game.Source})
+
+	for _, t := range optional {
+		if t.Value != "" {
+			tags = append(tags, t)
+		}
+	}
+
+	for _, t := range game.ExtraTags {
+		tags = append(tags, tag{t.Name, t.Value})
+	}
+
+	return tags
+}
+
+// formatPGN writes the game in pgn export format: the seven tag roster
+// followed by the optional tags and the extra tags with their values escaped,
+// and the movetext wrapped at 80 columns.
+func formatPGN(game *types.Game, options Options) string {
+	var sb strings.Builder
+
+	for _, t := range gameTags(game) {
+		fmt.Fprintf(&sb, "[%s \"%s\"]\n", t.Name, escapeTag(t.Value))
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(formatMovetext(game, options))
+	sb.WriteString("\n\n")
+
+	return sb.String()
+}
+
+var tagEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", " ", "\r", "")
+
+func escapeTag(value string) string {
+	return tagEscaper.Replace(value)
+}
+
+// suffixNAGs maps move suffix annotations to the NAGs export format uses
+// instead.
+var suffixNAGs = map[string]string{
+	"!":  "$1",
+	"?":  "$2",
+	"!!": "$3",
+	"??": "$4",
+	"!?": "$5",
+	"?!": "$6",
+}
+
+const lineWidth = 80
+
+// formatMovetext rewrites the movetext of the game in export format. Move
+// numbers are regenerated, a black move is numbered at the start of the game
+// or a variation and after a comment or variation, and the game always ends
+// in a termination marker.
+func formatMovetext(game *types.Game, options Options) string {
+	converter := newMoveConverter(game.FEN, options)
+	language, translated := chess.Languages[strings.ToLower(options.Language)]
+	ply := startingPly(game.FEN)
+	numbered := false
+	terminated := false
+
+	var plies []int
+	var words []string
+
+	for _, token := range parser.ParseMovetext(game.Game) {
+		switch token.Kind {
+		case parser.MoveNumberToken:
+			continue
+
+		case parser.MoveToken:
+			// the move number is kept on the same line as its move
+			move := converter.convert(token.Text)
+			if translated {
+				move = chess.TranslateSAN(move, chess.English, language)
+			}
+			if ply%2 == 0 {
+				move = fmt.Sprintf("%d. %s", ply/2+1, move)
+			} else if !numbered {
+				move = fmt.Sprintf("%d... %s", ply/2+1, move)
+			}
+
+			words = append(words, move)
+			numbered = true
+			ply++
+
+		case parser.CommentToken:
+			comment := strings.Fields(token.Text)
+			if len(comment) == 0 {
+				continue
+			}
+
+			comment[0] = "{" + comment[0]
+			comment[len(comment)-1] += "}"
+			words = append(words, comment...)
+			numbered = false
+
+		case parser.NAGToken:
+			if nag, exists := suffixNAGs[token.Text]; exists {
+				words = append(words, nag)
+				continue
+			}
+			words = append(words, token.Text)
+
+		case parser.VariationStartToken:
+			// a variation replaces the move played before it
+			plies = append(plies, ply)
+			ply = max(ply-1, 0)
+			converter.enter()
+			words = append(words, "(")
+			numbered = false
+
+		case parser.VariationEndToken:
+			if len(plies) > 0 {
+				ply = plies[len(plies)-1]
+				plies = plies[:len(plies)-1]
+			}
+			converter.exit()
+			words = append(words, ")")
+			numbered = false
+
+		case parser.ResultToken:
+			words = append(words, token.Text)
+			terminated = true
+		}
+	}
+
+	if !terminated {
+		result := game.Result
+		if result == "" || result == "?" {
+			result = "*"
+		}
+		words = append(words, result)
+	}
+
+	return wrap(words)
+}
+
+// startingPly is the number of plies played before the first move, games
+// from a custom position continue from its move number.
+func startingPly(fen string) int {
+	if fen == "" {
+		return 0
+	}
+
+	fields := strings.Fields(fen)
+	if len(fields) < 6 {
+		return 0
+	}
+
+	fullmove, err := strconv.Atoi(fields[5])
+	if err != nil || fullmove < 1 {
+		return 0
+	}
+
+	ply := (fullmove - 1) * 2
+	if fields[1] == "b" {
+		ply++
 	}
 
-	if game.Source != "" {
-		out += "\n"
-		out += fmt.Sprintf(`[Source "%s"]`, game.Source)
+	return ply
+}
+
+// wrap joins the words into lines of at most 80 columns, parentheses stick to
+// the word next to them.
+func wrap(words []string) string {
+	var sb strings.Builder
+	lineLen := 0
+
+	for i, word := range words {
+		sep := " "
+		if i == 0 || word == ")" || (i > 0 && words[i-1] == "(") {
+			sep = ""
+		}
+
+		width := utf8.RuneCountInString(word)
+
+		if lineLen > 0 && lineLen+len(sep)+width > lineWidth {
+			sb.WriteString("\n")
+			lineLen = 0
+			sep = ""
+		}
+
+		sb.WriteString(sep)
+		sb.WriteString(word)
+		lineLen += len(sep) + width
 	}
 
-	out += fmt.Sprintf("\n%s\n\n", game.Game)
-	return out
+	return sb.String()
 }
@@ -0,0 +1,220 @@
+package writer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gavink97/pgn-tools/internal/parser"
+	"github.com/gavink97/pgn-tools/internal/types"
+)
+
+var sampleGame = &types.Game{
+	Event:  "Tata Steel Masters",
+	Site:   "Wijk aan Zee NED",
+	Date:   "2023.01.14",
+	Round:  "1",
+	White:  "Carlsen, Magnus",
+	Black:  "Giri, Anish",
+	Result: "1/2-1/2",
+	Game:   "1. e4 e5 1/2-1/2",
+}
+
+func TestWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "games.pgn")
+
+	err := os.WriteFile(path, []byte("previous\n"), 0600)
+	if err != nil {
+		t.Fatalf("An error occured writing file: %v", err)
+	}
+
+	w, err := NewWriter(path, nil)
+	if err != nil {
+		t.Fatalf("An error occured creating writer: %v", err)
+	}
+
+	err = w.Write(sampleGame)
+	if err != nil {
+		t.Fatalf("An error occured writing game: %v", err)
+	}
+
+	err = w.Suspend()
+	if err != nil {
+		t.Fatalf("An error occured suspending writer: %v", err)
+	}
+
+	err = w.Write(sampleGame)
+	if err != nil {
+		t.Fatalf("An error occured writing game: %v", err)
+	}
+
+	// the output is untouched until the writer is closed
+	content, _ := os.ReadFile(path)
+	if string(content) != "previous\n" {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", string(content), "previous\n")
+	}
+
+	err = w.Close()
+	if err != nil {
+		t.Fatalf("An error occured closing writer: %v", err)
+	}
+
+	content, _ = os.ReadFile(path)
+	expected := strings.Repeat(formatPGN(sampleGame, Options{}), 2)
+	if string(content) != expected {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", string(content), expected)
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", len(entries), 1)
+	}
+}
+
+func TestWriterAbort(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "games.pgn")
+
+	w, err := NewWriter(path, nil)
+	if err != nil {
+		t.Fatalf("An error occured creating writer: %v", err)
+	}
+
+	err = w.Write(sampleGame)
+	if err != nil {
+		t.Fatalf("An error occured writing game: %v", err)
+	}
+
+	err = w.Abort()
+	if err != nil {
+		t.Fatalf("An error occured aborting writer: %v", err)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", len(entries), 0)
+	}
+}
+
+func TestSinkAbort(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "games.pgn")
+
+	player := func(game *types.Game) ([]string, error) {
+		return []string{game.White, game.Black}, nil
+	}
+
+	for _, sink := range []Sink{NewFileSink(path, nil), NewSplitSink(path, nil, player)} {
+		err := sink.Write(sampleGame)
+		if err != nil {
+			t.Fatalf("An error occured writing game: %v", err)
+		}
+
+		err = sink.Abort()
+		if err != nil {
+			t.Fatalf("An error occured aborting sink: %v", err)
+		}
+
+		entries, _ := os.ReadDir(dir)
+		if len(entries) != 0 {
+			t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", len(entries), 0)
+		}
+	}
+}
+
+func TestSplitSink(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "games.pgn")
+
+	player := func(game *types.Game) ([]string, error) {
+		return []string{game.White, game.Black}, nil
+	}
+
+	sink := NewSplitSink(path, nil, player)
+
+	for _, game := range []*types.Game{
+		{White: "Carlsen, Magnus", Black: "Carlsen, Magnus", Result: "*", Game: "*"},
+		{White: "Carlsen Magnus", Black: "?", Result: "*", Game: "*"},
+	} {
+		err := sink.Write(game)
+		if err != nil {
+			t.Fatalf("An error occured writing game: %v", err)
+		}
+	}
+
+	err := sink.Close()
+	if err != nil {
+		t.Fatalf("An error occured closing sink: %v", err)
+	}
+
+	for file, expected := range map[string]int{
+		"games_Carlsen_Magnus.pgn":   1,
+		"games_Carlsen_Magnus_2.pgn": 1,
+		"games_unknown.pgn":          1,
+	} {
+		games, err := parser.ParsePGN(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatalf("An error occured reading %s: %v", file, err)
+		}
+
+		if len(games) != expected {
+			t.Errorf("Incorrect Result for %s: \nresult: %v \nexpected: %v", file, len(games), expected)
+		}
+	}
+}
+
+func TestWriterGzip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "games.json.gz")
+
+	format, err := FormatOf(path, Options{})
+	if err != nil {
+		t.Fatalf("An error occured getting format: %v", err)
+	}
+
+	if format.Extension() != ".json" {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", format.Extension(), ".json")
+	}
+
+	w, err := NewWriter(path, pgnFormat{})
+	if err != nil {
+		t.Fatalf("An error occured creating writer: %v", err)
+	}
+
+	// a suspended writer continues in a new gzip member
+	for range 2 {
+		err = w.Write(sampleGame)
+		if err != nil {
+			t.Fatalf("An error occured writing game: %v", err)
+		}
+
+		err = w.Suspend()
+		if err != nil {
+			t.Fatalf("An error occured suspending writer: %v", err)
+		}
+	}
+
+	err = w.Close()
+	if err != nil {
+		t.Fatalf("An error occured closing writer: %v", err)
+	}
+
+	games, err := parser.ParsePGN(path)
+	if err != nil {
+		t.Fatalf("An error occured reading gzip: %v", err)
+	}
+
+	if len(games) != 2 || games[1].White != sampleGame.White {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", len(games), 2)
+	}
+
+	result := FormatPath("games_ruy.pgn.gz", format)
+	if result != "games_ruy.json.gz" {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", result, "games_ruy.json.gz")
+	}
+
+	result = SplitPath("games.pgn.gz", "2023")
+	if result != "games_2023.pgn.gz" {
+		t.Errorf("Incorrect Result: \nresult: %v \nexpected: %v", result, "games_2023.pgn.gz")
+	}
+}
@@ -0,0 +1,287 @@
+package writer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/gavink97/pgn-tools/internal/types"
+)
+
+// Sink receives the games produced by a command. Close finishes the output,
+// Abort discards it on an error so no temporary files are left behind.
+type Sink interface {
+	Write(game *types.Game) error
+	Close() error
+	Abort() error
+}
+
+// FileSink writes the games to a file with a Writer, the file is only created
+// once the first game is written.
+type FileSink struct {
+	Path   string
+	Format Format
+	writer *Writer
+}
+
+func NewFileSink(path string, format Format) *FileSink {
+	return &FileSink{Path: path, Format: format}
+}
+
+func (s *FileSink) Write(game *types.Game) error {
+	if s.writer == nil {
+		w, err := NewWriter(s.Path, s.Format)
+		if err != nil {
+			return err
+		}
+		s.writer = w
+	}
+
+	return s.writer.Write(game)
+}
+
+func (s *FileSink) Close() error {
+	if s.writer == nil {
+		return nil
+	}
+
+	return s.writer.Close()
+}
+
+func (s *FileSink) Abort() error {
+	if s.writer == nil {
+		return nil
+	}
+
+	return s.writer.Abort()
+}
+
+type StreamSink struct {
+	Out    io.Writer
+	Format Format
+	count  int
+	begun  bool
+}
+
+// NewStreamSink creates a sink streaming the format to out, a nil format
+// streams pgn.
+func NewStreamSink(out io.Writer, format Format) *StreamSink {
+	if format == nil {
+		format = pgnFormat{}
+	}
+
+	return &StreamSink{Out: out, Format: format}
+}
+
+func (s *StreamSink) begin() error {
+	if s.begun {
+		return nil
+	}
+
+	s.begun = true
+	return s.Format.Begin(s.Out)
+}
+
+func (s *StreamSink) Write(game *types.Game) error {
+	err := s.begin()
+	if err != nil {
+		return err
+	}
+
+	err = s.Format.WriteGame(s.Out, game, s.count)
+	if err != nil {
+		return err
+	}
+
+	s.count++
+	return nil
+}
+
+func (s *StreamSink) Close() error {
+	err := s.begin()
+	if err != nil {
+		return err
+	}
+
+	return s.Format.End(s.Out)
+}
+
+// Abort leaves the stream as it is, the games already written can not be
+// taken back.
+func (s *StreamSink) Abort() error {
+	return nil
+}
+
+// CountSink discards the games and prints how many it received on close,
+// prefixed by the label when one is set.
+type CountSink struct {
+	Out   io.Writer
+	Label string
+	Count int
+}
+
+func NewCountSink(out io.Writer, label string) *CountSink {
+	return &CountSink{Out: out, Label: label}
+}
+
+func (s *CountSink) Write(game *types.Game) error {
+	s.Count++
+	return nil
+}
+
+func (s *CountSink) Close() error {
+	if s.Label != "" {
+		_, err := fmt.Fprintf(s.Out, "%s: %d\n", s.Label, s.Count)
+		return err
+	}
+
+	_, err := fmt.Fprintln(s.Out, s.Count)
+	return err
+}
+
+func (s *CountSink) Abort() error {
+	return nil
+}
+
+// maxOpenSplits is the number of split files kept open at once, the least
+// recently opened file is suspended when another one is needed.
+const maxOpenSplits = 64
+
+// SplitSink writes every game into one file per distinct value of its key,
+// a game with multiple values such as both players is written to each file
+// once. Distinct values whose file names would collide get a numbered suffix.
+type SplitSink struct {
+	Path    string
+	Format  Format
+	Key     func(*types.Game) ([]string, error)
+	writers map[string]*Writer
+	open    []*Writer
+	names   map[string]string
+	taken   map[string]bool
+}
+
+func NewSplitSink(path string, format Format, key func(*types.Game) ([]string, error)) *SplitSink {
+	return &SplitSink{
+		Path:    path,
+		Format:  format,
+		Key:     key,
+		writers: map[string]*Writer{},
+		names:   map[string]string{},
+		taken:   map[string]bool{},
+	}
+}
+
+func (s *SplitSink) Write(game *types.Game) error {
+	values, err := s.Key(game)
+	if err != nil {
+		return err
+	}
+
+	var written []string
+
+	for _, value := range values {
+		name := s.fileName(value)
+		if slices.Contains(written, name) {
+			continue
+		}
+		written = append(written, name)
+
+		w, err := s.writer(name)
+		if err != nil {
+			return err
+		}
+
+		err = w.Write(game)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fileName returns the name a value is written under, names are compared
+// without case so they stay distinct on case insensitive file systems.
+func (s *SplitSink) fileName(value string) string {
+	name, exists := s.names[value]
+	if exists {
+		return name
+	}
+
+	base := sanitizeFileName(value)
+	name = base
+	for n := 2; s.taken[strings.ToLower(name)]; n++ {
+		name = fmt.Sprintf("%s_%d", base, n)
+	}
+
+	s.names[value] = name
+	s.taken[strings.ToLower(name)] = true
+	return name
+}
+
+func (s *SplitSink) writer(name string) (*Writer, error) {
+	w, exists := s.writers[name]
+	if !exists {
+		var err error
+		w, err = NewWriter(SplitPath(s.Path, name), s.Format)
+		if err != nil {
+			return nil, err
+		}
+		s.writers[name] = w
+	} else if slices.Contains(s.open, w) {
+		return w, nil
+	}
+
+	if len(s.open) == maxOpenSplits {
+		err := s.open[0].Suspend()
+		if err != nil {
+			return nil, err
+		}
+		s.open = s.open[1:]
+	}
+
+	s.open = append(s.open, w)
+	return w, nil
+}
+
+func (s *SplitSink) Close() error {
+	var errs []error
+
+	for _, w := range s.writers {
+		errs = append(errs, w.Close())
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *SplitSink) Abort() error {
+	var errs []error
+
+	for _, w := range s.writers {
+		errs = append(errs, w.Abort())
+	}
+
+	return errors.Join(errs...)
+}
+
+// SplitPath inserts the name between the base name and extension of path.
+func SplitPath(path string, name string) string {
+	trimmed := trimGzip(path)
+	ext := filepath.Ext(trimmed) + path[len(trimmed):]
+	return fmt.Sprintf("%s_%s%s", strings.TrimSuffix(path, ext), name, ext)
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^\p{L}\p{N}._-]+`)
+
+func sanitizeFileName(value string) string {
+	name := strings.Trim(unsafeFileChars.ReplaceAllString(strings.TrimSpace(value), "_"), "_.")
+	if name == "" {
+		return "unknown"
+	}
+
+	return name
+}